@@ -0,0 +1,82 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// activitySize bounds how many API requests the activity log remembers.
+const activitySize = 50
+
+var repoPathRE = regexp.MustCompile(`repos/([^/]+/[^/?]+)`)
+
+// get is ghApiClient.Get that respects the rate limit and also shows up in
+// the activity log.
+func (client *Client) get(ghApiClient *api.RESTClient, path string, response interface{}) error {
+	_, err := client.throttled(func() (http.Header, error) {
+		httpResponse, err := ghApiClient.Request(http.MethodGet, path, nil)
+		client.logActivity(http.MethodGet, path, statusOf(err, http.StatusOK))
+		if err != nil {
+			return nil, err
+		}
+		defer httpResponse.Body.Close()
+		if httpResponse.StatusCode == http.StatusNoContent {
+			return httpResponse.Header, nil
+		}
+		return httpResponse.Header, json.NewDecoder(httpResponse.Body).Decode(response)
+	})
+	return err
+}
+
+// statusOf is the HTTP status of a request that returned err, 0 if there
+// was no response at all.
+func statusOf(err error, ok int) int {
+	if err == nil {
+		return ok
+	}
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+// logActivity remembers an API request for the activity panel, only the
+// most recent ones are kept.
+func (client *Client) logActivity(method, path string, status int) {
+	target := path
+	if m := repoPathRE.FindStringSubmatch(path); m != nil {
+		target = m[1]
+		if client.anonymizer != nil {
+			target = client.anonymizer.placeholder("repo", target)
+		}
+	}
+	code := "---"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	event := fmt.Sprintf("%s %-6s %s %s", time.Now().Format(time.TimeOnly), method, code, target)
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.activity = append(client.activity, event)
+	if len(client.activity) > activitySize {
+		client.activity = client.activity[len(client.activity)-activitySize:]
+	}
+}
+
+// RecentActivity returns up to n of the most recent API requests, oldest
+// first.
+func (client *Client) RecentActivity(n int) []string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	start := max(len(client.activity)-n, 0)
+	return append([]string{}, client.activity[start:]...)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ageValue is a duration flag that also understands days and weeks, like
+// 30d or 2w, on top of everything time.ParseDuration accepts.
+type ageValue time.Duration
+
+func newAgeValue(d time.Duration, p *time.Duration) *ageValue {
+	*p = d
+	return (*ageValue)(p)
+}
+
+func (a *ageValue) Set(s string) error {
+	d, err := parseAge(s)
+	if err != nil {
+		return err
+	}
+	*a = ageValue(d)
+	return nil
+}
+
+func (a *ageValue) String() string {
+	d := time.Duration(*a)
+	if d != 0 && d%day == 0 {
+		return strconv.Itoa(int(d/day)) + "d"
+	}
+	return d.String()
+}
+
+func (a *ageValue) Type() string {
+	return "age"
+}
+
+func parseAge(s string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": day, "w": 7 * day}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			count, err := strconv.ParseFloat(n, 64)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid age %q", s)
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q, use e.g. 12h, 30d or 2w", s)
+	}
+	return d, nil
+}
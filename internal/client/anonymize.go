@@ -0,0 +1,63 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// anonymizer replaces repository names, titles and logins with
+// placeholders for --anonymize. The same name gets the same placeholder
+// within a run, the salt keeps the placeholders from being reversed.
+type anonymizer struct {
+	salt []byte
+}
+
+func newAnonymizer() (*anonymizer, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot anonymize: %w", err)
+	}
+	return &anonymizer{salt: salt}, nil
+}
+
+func (a *anonymizer) placeholder(kind, name string) string {
+	if name == "" {
+		return ""
+	}
+	sum := sha256.Sum256(append(append([]byte{}, a.salt...), kind+"\x00"+name...))
+	return kind + "-" + hex.EncodeToString(sum[:2])
+}
+
+// result scrubs a result, keeping its structure and decisions.
+func (a *anonymizer) result(res NotificationResult) NotificationResult {
+	repo := res.Notification.Repository.FullName
+	anonRepo := a.placeholder("repo", repo)
+	res.Notification.Repository.FullName = anonRepo
+	res.Notification.Subject.Title = a.placeholder("title", res.Notification.Subject.Title)
+	// subject URLs contain the repository
+	res.Notification.Subject.Url = ""
+	res.Notification.Subject.LatestCommentUrl = ""
+	res.RenamedFrom = a.placeholder("repo", res.RenamedFrom)
+	if res.PR != nil {
+		pr := *res.PR
+		pr.User.Login = a.placeholder("user", pr.User.Login)
+		pr.Base.Repo.FullName = anonRepo
+		res.PR = &pr
+	}
+	if res.Err != nil && repo != "" {
+		scrubbed := errors.New(strings.ReplaceAll(res.Err.Error(), repo, anonRepo))
+		if apiErr, ok := res.Err.(*APIError); ok {
+			// keep it usable with errors.Is
+			anonErr := *apiErr
+			anonErr.Err = scrubbed
+			res.Err = &anonErr
+		} else {
+			res.Err = scrubbed
+		}
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Errors that an APIError matches with errors.Is besides ErrNoAuth, for
+// programmatic consumers.
+var (
+	ErrRateLimited       = errors.New("GitHub API rate limit exceeded")
+	ErrInsufficientScope = errors.New("token lacks the permissions for this request")
+)
+
+// APIError is a failed request to the GitHub API. It unwraps to the
+// underlying error, an *api.HTTPError if GitHub responded and a network
+// error otherwise.
+type APIError struct {
+	// Endpoint names the request, e.g. "DELETE thread".
+	Endpoint string
+	// StatusCode is the HTTP status, 0 if there was no response at all.
+	StatusCode int
+	Err        error
+
+	rateLimited bool
+}
+
+func newAPIError(endpoint string, err error) *APIError {
+	apiErr := &APIError{Endpoint: endpoint, Err: err}
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) {
+		apiErr.StatusCode = httpErr.StatusCode
+		apiErr.rateLimited = isRateLimited(httpErr)
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrNoAuth, ErrRateLimited and ErrInsufficientScope by status.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNoAuth:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.rateLimited
+	case ErrInsufficientScope:
+		return e.StatusCode == http.StatusForbidden && !e.rateLimited
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"unauthorized", &api.HTTPError{StatusCode: http.StatusUnauthorized}, ErrNoAuth, true},
+		{"forbidden", &api.HTTPError{StatusCode: http.StatusForbidden}, ErrInsufficientScope, true},
+		{"forbidden is not auth", &api.HTTPError{StatusCode: http.StatusForbidden}, ErrNoAuth, false},
+		{"too many requests", &api.HTTPError{StatusCode: http.StatusTooManyRequests}, ErrRateLimited, true},
+		{"rate limit is not scope", &api.HTTPError{StatusCode: http.StatusForbidden, Headers: http.Header{"X-Ratelimit-Remaining": {"0"}}}, ErrInsufficientScope, false},
+		{"secondary rate limit", &api.HTTPError{StatusCode: http.StatusForbidden, Message: "You have exceeded a secondary rate limit"}, ErrRateLimited, true},
+		{"not found", &api.HTTPError{StatusCode: http.StatusNotFound}, ErrInsufficientScope, false},
+		{"network", errors.New("connection refused"), ErrNoAuth, false},
+		{"wrapped scope", fmt.Errorf("%w, it has: read:org", ErrInsufficientScope), ErrInsufficientScope, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newAPIError(endpointThread, tt.err)
+			if got := errors.Is(err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+var ErrNoAuth = errors.New("no GitHub authentication found; set GH_TOKEN or run gh auth login")
+
+// Validate checks up front that we can talk to GitHub, so that missing
+// authentication doesn't surface as a panic inside a worker.
+func (client *Client) Validate() error {
+	for _, host := range client.hosts() {
+		if token, _ := auth.TokenForHost(host); token == "" {
+			return ErrNoAuth
+		}
+		if _, err := client.restClient(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// currentUser returns the login of the authenticated user on a host, it is
+// only looked up once per host.
+func (client *Client) currentUser(ghApiClient *api.RESTClient, host string) (string, error) {
+	client.mu.Lock()
+	user, ok := client.logins[host]
+	if !ok {
+		if client.logins == nil {
+			client.logins = map[string]*hostLogin{}
+		}
+		user = &hostLogin{}
+		client.logins[host] = user
+	}
+	client.mu.Unlock()
+	user.once.Do(func() {
+		response := struct{ Login string }{}
+		if err := client.get(ghApiClient, "user", &response); err != nil {
+			user.err = client.recordAPIError(endpointUser, err)
+		}
+		user.login = response.Login
+	})
+	return user.login, user.err
+}
+
+func (client *Client) ownPR(ghApiClient *api.RESTClient, host string, pr *PullRequest) (bool, error) {
+	login, err := client.currentUser(ghApiClient, host)
+	return err == nil && strings.EqualFold(pr.User.Login, login), err
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		env     map[string]string
+		wantErr error
+	}{
+		{"no token", "github.com", nil, ErrNoAuth},
+		{"GH_TOKEN", "github.com", map[string]string{"GH_TOKEN": "token"}, nil},
+		{"GITHUB_TOKEN", "github.com", map[string]string{"GITHUB_TOKEN": "token"}, nil},
+		{"enterprise without token", "github.example.com", map[string]string{"GH_TOKEN": "token"}, ErrNoAuth},
+		{"enterprise token", "github.example.com", map[string]string{"GH_ENTERPRISE_TOKEN": "token"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// no config, no gh to ask for a token
+			t.Setenv("GH_CONFIG_DIR", t.TempDir())
+			t.Setenv("PATH", "")
+			for _, name := range []string{"GH_TOKEN", "GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+				t.Setenv(name, tt.env[name])
+			}
+			client := &Client{opts: &Options{Hostnames: []string{tt.host}}}
+
+			if err := client.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
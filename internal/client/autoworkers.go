@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// rampUpAfter is how many deletions in a row have to go through without
+// rate limit trouble before another worker is let in.
+const rampUpAfter = 10
+
+// workerGate limits how many workers may delete at the same time for
+// --auto-workers. It starts low, lets more workers in while the rate limit
+// has headroom and backs off when it runs low or GitHub pushes back.
+type workerGate struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	active    int
+	limit     int
+	min, max  int
+	successes int
+}
+
+// newWorkerGate returns nil, which never blocks, unless auto-tuning is on.
+func newWorkerGate(enabled bool, max int) *workerGate {
+	if !enabled {
+		return nil
+	}
+	g := &workerGate{min: 1, max: max, limit: min(2, max)}
+	g.cond = sync.NewCond(&g.mu)
+	return g
+}
+
+func (g *workerGate) acquire() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for g.active >= g.limit {
+		g.cond.Wait()
+	}
+	g.active++
+}
+
+// release frees a slot and adjusts the limit to the response of the call
+// made while holding it.
+func (g *workerGate) release(header http.Header, err error) {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	defer g.cond.Broadcast()
+
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusForbidden || httpErr.StatusCode == http.StatusTooManyRequests) {
+		g.limit = max(g.min, g.limit/2)
+		g.successes = 0
+		return
+	}
+	if rateLimitLow(header) {
+		g.limit = max(g.min, g.limit-1)
+		g.successes = 0
+		return
+	}
+	if err == nil {
+		g.successes++
+		if g.successes >= rampUpAfter && g.limit < g.max {
+			g.limit++
+			g.successes = 0
+		}
+	}
+}
+
+// Limit is the number of workers currently allowed to delete.
+func (g *workerGate) Limit() int {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.limit
+}
+
+// rateLimitLow reports whether less than a tenth of the rate limit is left.
+func rateLimitLow(header http.Header) bool {
+	remaining, err1 := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	limit, err2 := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	return err1 == nil && err2 == nil && remaining*10 < limit
+}
+
+// SettledWorkers is the number of delete workers --auto-workers settled on,
+// or 0 without --auto-workers.
+func (client *Client) SettledWorkers() int {
+	return client.workerGate.Limit()
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type backupRecord struct {
+	DeletedAt time.Time `json:"deleted_at"`
+	Notification
+}
+
+// backupFile is the --auto-backup file for the given day.
+func backupFile(t time.Time) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, t.Format(time.DateOnly)+".jsonl"), nil
+}
+
+// backup appends a deleted notification to today's backup file.
+func (client *Client) backup(notification Notification) {
+	if !client.opts.AutoBackup {
+		return
+	}
+	now := time.Now()
+	line, err := json.Marshal(backupRecord{DeletedAt: now.UTC(), Notification: notification})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot back up deleted notification:", err)
+		return
+	}
+	fileName, err := backupFile(now)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot back up deleted notification:", err)
+		return
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(fileName), 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot back up deleted notification:", err)
+		return
+	}
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot back up deleted notification:", err)
+	}
+}
+
+// purgeBackups removes the backup files of days older than
+// --purge-backups-older-than.
+func (client *Client) purgeBackups() {
+	if client.opts.PurgeBackupsOlderThan <= 0 || client.opts.DryRun {
+		return
+	}
+	dir, err := stateDir()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot purge backups:", err)
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot purge backups:", err)
+		return
+	}
+	cutoff := time.Now().Add(-client.opts.PurgeBackupsOlderThan)
+	for _, fileName := range files {
+		// only touch files named like backups
+		date, err := time.ParseInLocation(time.DateOnly, strings.TrimSuffix(filepath.Base(fileName), ".jsonl"), time.Local)
+		if err != nil || !date.AddDate(0, 0, 1).Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(fileName); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush: cannot purge backups:", err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// RingBell rings the terminal bell with --bell and shows a desktop
+// notification with --notify-desktop once a run is done.
+func (client *Client) RingBell() {
+	if client.opts.Bell {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	if client.opts.NotifyDesktop {
+		message := fmt.Sprintf("Processed %d notifications, flushed %d", client.numProcessed.Load(), client.numDeleted.Load())
+		notifyDesktop("gh flush is done", message)
+	}
+}
+
+// notifyDesktop uses the notifier of the OS if there is one, and silently
+// does nothing otherwise.
+func notifyDesktop(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.Command("notify-send", title, message)
+	default:
+		return
+	}
+	if cmd.Err != nil {
+		// the notifier isn't installed
+		return
+	}
+	_ = cmd.Run()
+}
@@ -1,12 +1,18 @@
 package client
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,11 +21,31 @@ import (
 	"github.com/cli/go-gh/v2/pkg/api"
 )
 
+// endpoints as reported in APIErrorSummary
 const (
-	BotPR    = "🤖"
-	ClosedPR = "✅"
-	Read     = "👓"
-	Deleted  = "❌"
+	endpointNotifications = "GET notifications"
+	endpointPullRequest   = "GET pull request"
+	endpointThread        = "DELETE thread"
+	endpointReviews       = "GET reviews"
+	endpointComment       = "GET comment"
+	endpointIssue         = "GET issue"
+	endpointSubscription  = "GET subscription"
+	endpointUser          = "GET user"
+)
+
+const (
+	BotPR       = "🤖"
+	ClosedPR    = "✅"
+	ClosedIssue = "☑"
+	MergedPR    = "🔀"
+	Read        = "👓"
+	Deleted     = "❌"
+	Protected   = "🛡"
+	Failed      = "⚠"
+	Recent      = "🕑"
+	Active      = "💬"
+	Stale       = "🕸"
+	NewActivity = "🆕"
 )
 
 func NewClient() *Client {
@@ -30,6 +56,18 @@ func NewClient() *Client {
 	client.results = make(chan NotificationResult)
 	client.wgFetcher = new(sync.WaitGroup)
 	client.wgDeleter = new(sync.WaitGroup)
+	client.deletePacer = newPacer(client.opts.DeleteRate)
+	client.workerGate = newWorkerGate(client.opts.AutoWorkers, client.opts.NumWorkers)
+	if client.opts.Anonymize {
+		var err error
+		if client.anonymizer, err = newAnonymizer(); err != nil {
+			exitWithError(err)
+		}
+	}
+	if client.opts.ReportFile != "" {
+		client.report = &reportFile{fileName: client.opts.ReportFile, format: client.opts.ReportFormat}
+	}
+	client.checkCommitLog()
 	return client
 }
 
@@ -37,11 +75,106 @@ func parseOptions() *Options {
 	opts := new(Options)
 	flag.BoolVarP(&opts.SkipPRsFromBots, "skip-bots", "b", false, "don't delete notifications on PRs from bots")
 	flag.BoolVarP(&opts.SkipClosedPRs, "skip-closed", "c", false, "don't delete notifications on closed / merged PRs")
+	flag.BoolVar(&opts.SkipUnmergedClosed, "skip-unmerged-closed", false, "don't delete notifications on PRs closed without merging")
+	flag.BoolVar(&opts.SkipClosedIssues, "skip-closed-issues", false, "don't delete notifications on closed issues")
 	flag.BoolVarP(&opts.SkipReadNotifications, "skip-read", "r", false, "don't delete read notifications")
+	flag.BoolVar(&opts.FlushOwnMerged, "flush-own-merged", false, "only delete notifications on your own pull requests once they are merged, instead of the built-in rules")
+	flag.BoolVar(&opts.FlushStaleDrafts, "flush-stale-drafts", false, "also delete notifications on draft pull requests not updated within --stale-draft-age")
+	flag.Var(newAgeValue(90*day, &opts.StaleDraftAge), "stale-draft-age", "how long a draft pull request has to be untouched to count as stale, e.g. 30d")
+	flag.BoolVar(&opts.FlushCommitComments, "flush-commit-comments", false, "also delete notifications on commits not updated within --commit-comment-age")
+	flag.Var(newAgeValue(30*day, &opts.CommitCommentAge), "commit-comment-age", "how long a commit notification has to be quiet to be flushed, e.g. 7d")
+	flag.BoolVar(&opts.FlushStateChanges, "flush-state-changes", false, "also delete state_change notifications once their issue or pull request is closed, regardless of the other rules")
+	flag.BoolVar(&opts.ConfirmPerRepo, "confirm-per-repo", false, "ask once per repository before flushing its notifications")
+	flag.BoolVar(&opts.Review, "interactive", false, "review the matching notifications one at a time before flushing them")
+	flag.BoolVar(&opts.Select, "select", false, "pick the matching notifications to flush from a checklist")
+	flag.BoolVar(&opts.InteractiveConfirm, "interactive-confirm", false, "list a sample of the matching notifications and ask before flushing them")
+	flag.DurationVar(&opts.UndoWindow, "undo-window", 0, "wait this long before deleting in a terminal, so that the flush can still be undone, e.g. 5s")
 	flag.BoolVarP(&opts.DryRun, "dry-run", "n", false, "dry run without deleting anything")
+	flag.BoolVar(&opts.CheckPermissions, "check-permissions", false, "dry run that also checks on a few matching notifications that your token could delete them")
+	flag.BoolVarP(&opts.Verbose, "verbose", "v", false, "report more details about the run")
+	flag.BoolVarP(&opts.Quiet, "quiet", "q", false, "don't report progress and notices on stderr, only errors")
+	flag.BoolVar(&opts.Progress, "progress", false, "report progress on stderr every few seconds when the output is not a terminal")
 	flag.IntVarP(&opts.NumWorkers, "workers", "w", runtime.NumCPU(), "number of workers")
-	// TODO get rid of this and store offsets in a file
+	flag.BoolVar(&opts.AutoWorkers, "auto-workers", false, "start with few delete workers and adapt to the rate limit, up to --workers")
+	flag.Float64Var(&opts.DeleteRate, "delete-rate", 5, "maximum deletions, or threads marked as read or unsubscribed from, per second, to stay clear of secondary rate limits, set to 0 for no limit")
 	flag.IntVarP(&opts.HaltAfter, "halt-after", "s", 50, "stop after a given number of read messages in a row, set to 0 to never stop")
+	flag.BoolVar(&opts.Incremental, "incremental", false, "only fetch the notifications updated since the last complete run instead of stopping with --halt-after")
+	flag.BoolVar(&opts.RESTLookups, "rest", false, "look up pull requests one REST request at a time instead of in batched GraphQL queries, with --repo this also finds pull requests of repositories renamed since")
+	flag.IntVar(&opts.PerPage, "per-page", 100, "fetch `N` notifications per page, at most 100")
+	flag.IntVar(&opts.MaxPages, "max-pages", 0, "stop fetching after `K` pages of notifications, set to 0 to fetch all")
+	before := flag.String("before", "", "only fetch notifications updated before this `time` (RFC 3339 or YYYY-MM-DD)")
+	flag.BoolVar(&opts.Participating, "participating", false, "only fetch notifications of threads you are directly participating in or mentioned in")
+	since := flag.String("since", "", "only fetch notifications updated after this `time` (RFC 3339 or YYYY-MM-DD)")
+	flag.StringSliceVar(&opts.Repos, "repo", nil, "only flush notifications from repositories matching these globs, e.g. `myorg/*`")
+	flag.StringSliceVar(&opts.ExcludeRepos, "exclude-repo", nil, "ignore notifications from repositories matching these globs")
+	orgs := flag.StringSlice("org", nil, "only flush notifications from repositories of these organizations or users, short for --repo org/*")
+	excludeOrgs := flag.StringSlice("exclude-org", nil, "ignore notifications from repositories of these organizations or users, short for --exclude-repo org/*")
+	flag.StringArrayVar(&opts.RepoTopics, "repo-topic", nil, "only flush notifications from repositories with this `topic` (repeatable)")
+	flag.StringVar(&opts.Team, "team", "", "only flush notifications from the repositories of this `org/team-slug`")
+	flag.StringSliceVar(&opts.ProtectRepos, "protect-repo", nil, "never delete notifications from repositories matching these globs")
+	flag.StringArrayVar(&opts.ProtectLabels, "protect-label", nil, "never delete notifications on issues and pull requests with this `label` (repeatable)")
+	repoFile := flag.String("repo-file", "", "read --repo patterns from a file, one per line")
+	excludeRepoFile := flag.String("exclude-repo-file", "", "read --exclude-repo patterns from a file, one per line")
+	protectRepoFile := flag.String("protect-repo-file", "", "read --protect-repo patterns from a file, one per line")
+	flag.StringArrayVar(&opts.DeleteWhen, "delete-when", nil, "delete notifications matching all `key=value,...` conditions instead of using the built-in rules (repeatable)")
+	flag.Float64Var(&opts.Estimate, "estimate", 0, "dry run on a random `P` percent of the notifications and extrapolate how many would be flushed")
+	flag.IntVar(&opts.Sample, "sample", 0, "only really delete a random sample of `N` matching notifications, dry-run the rest")
+	flag.Int64Var(&opts.Seed, "seed", 0, "random seed for --sample, defaults to the current time")
+	flag.IntVar(&opts.Limit, "limit", 0, "delete at most `N` notifications, keep the rest for the next run")
+	flag.StringVar(&opts.OrderBy, "order-by", OrderUpdated, "order to delete in, matters with --limit: updated (most recently updated first) or repo-volume (repositories with the most matches first)")
+	flag.IntVar(&opts.KeepRecentPerRepo, "keep-recent-per-repo", 0, "keep the `N` most recently updated matching notifications in each repository")
+	flag.BoolVar(&opts.SkipChangesRequested, "skip-changes-requested", false, "don't delete notifications on your pull requests with changes requested, costs an extra request per pull request")
+	flag.BoolVar(&opts.SkipLastCommentedByMe, "skip-last-commented-by-me", false, "don't delete notifications on pull requests and issues where you wrote the latest comment, costs an extra request per subject")
+	flag.BoolVar(&opts.UnreadSinceRead, "unread-since-read", false, "treat read notifications with new activity since they were read as unread and keep them")
+	flag.Var(newAgeValue(0, &opts.OlderThan), "older-than", "also delete notifications not updated within this `age`, e.g. 30d, whatever the other rules say")
+	flag.Var(newAgeValue(0, &opts.NewerThan), "newer-than", "never delete notifications updated within this `age`, e.g. 2d")
+	flag.Var(newAgeValue(0, &opts.KeepMentionedWithin), "keep-mentioned-within", "never delete mentions updated within this `age`, e.g. 7d")
+	flag.IntVar(&opts.ActiveThreshold, "active-threshold", 0, "never delete notifications on pull requests or issues with more than `N` comments")
+	flag.BoolVar(&opts.Unsubscribe, "unsubscribe", false, "also unsubscribe from the threads of deleted notifications, so new comments don't bring them back")
+	flag.BoolVar(&opts.ShowSubscription, "show-subscription", false, "show whether you are subscribed to, watching or ignoring each thread, costs an extra request per notification")
+	flag.StringVar(&opts.ProgressStyle, "progress-style", ProgressBar, "how to show progress in a terminal: bar, percentage, spinner-only or none")
+	flag.StringVar(&opts.Format, "format", FormatTable, "output `format` when not running in a terminal: table, json, ndjson (one notification per line), csv, markdown or a Go template like --template, also --output")
+	flag.StringVar(&opts.ReportFile, "report-file", "", "also write the results to a `file`, in --report-format")
+	flag.StringVar(&opts.ReportFormat, "report-format", FormatJSON, "`format` of --report-file: json or markdown")
+	flag.StringSliceVar(&opts.JSONFields, "json-fields", nil, "only include these `fields` of each notification in --format json, e.g. repo,title,deleted")
+	flag.StringVar(&opts.Template, "template", "", "format each result with a Go `template`, e.g. '{{.Action}} {{.Repo}} {{.Title}}', or one of the named templates compact, tsv, links")
+	flag.BoolVar(&opts.StrictNothing, "strict-nothing", false, "exit with code 3 when no notification matched the rules")
+	flag.BoolVar(&opts.FailOnAnyError, "fail-on-any-error", false, "exit with code 5 when any API request failed, not only the lookups and deletions of notifications")
+	flag.BoolVar(&opts.ShowUnflushedRepos, "show-unflushed-repos", false, "list the repositories where nothing was flushed and why, after the results")
+	flag.BoolVar(&opts.Bell, "bell", false, "ring the terminal bell when done")
+	flag.BoolVar(&opts.NotifyDesktop, "notify-desktop", false, "show a desktop notification when done, if the OS has a notifier")
+	flag.Bool("line-buffered", false, "write each result as soon as it is known")
+	flag.CommandLine.MarkDeprecated("line-buffered", "results are always written as soon as they are known")
+	flag.BoolVar(&opts.Anonymize, "anonymize", false, "replace repository names, titles and logins with placeholders in the output, for sharing")
+	flag.BoolVar(&opts.Summary, "summary", false, "print a summary after the results when not running in a terminal")
+	flag.StringArrayVar(&opts.Queries, "query", nil, "delete notifications matching a `name`d query from the config file, like --delete-when (repeatable)")
+	flag.BoolVar(&opts.Rules, "rules", false, "decide with the rules from the config file instead of the built-in rules")
+	flag.StringSliceVar(&opts.Hostnames, "hostname", nil, "the GitHub `host` to flush, e.g. a GitHub Enterprise Server, defaults to GH_HOST or the host gh is logged in to (repeatable)")
+	flag.StringVar(&opts.Mode, "mode", ModeDelete, "what to do with matching notifications: delete, done (the same, they stay under Done on github.com) or read to only mark them as read")
+	listFlagValues := flag.String("list-flag-values", "", "print the valid values of a `flag`, for shell completion")
+	flag.CommandLine.MarkHidden("list-flag-values")
+	// --output is what gh itself calls it
+	flag.CommandLine.SetNormalizeFunc(func(f *flag.FlagSet, name string) flag.NormalizedName {
+		if name == "output" {
+			name = "format"
+		}
+		return flag.NormalizedName(name)
+	})
+	listQueries := flag.Bool("list-queries", false, "list the queries defined in the config file and exit")
+	flag.StringVar(&opts.Subject, "subject", "", "only delete the notification about this issue or pull request, as `owner/repo#123` or URL")
+	flag.StringVar(&opts.DumpNotifications, "dump-notifications", "", "write the fetched notifications to a JSON `file` before flushing anything")
+	flag.BoolVar(&opts.ListTypes, "list-types", false, "list the subject types and reasons of your notifications and exit without deleting anything")
+	flag.BoolVar(&opts.AutoBackup, "auto-backup", false, "append deleted notifications to a file per day in the state directory, e.g. ~/.local/state/gh-flush/2024-06-12.jsonl")
+	flag.Var(newAgeValue(0, &opts.PurgeBackupsOlderThan), "purge-backups-older-than", "remove --auto-backup files older than this, e.g. 90d")
+	flag.BoolVar(&opts.Plan, "plan", false, "print the planned deletions as JSON without deleting anything, see --apply-plan")
+	flag.StringVar(&opts.ApplyPlan, "apply-plan", "", "delete exactly the notifications in a `file` written by --plan that still exist")
+	flag.BoolVar(&opts.Continue, "continue", false, "continue an interrupted run, skipping the notifications it already deleted")
+	flag.StringVar(&opts.VerifyPlan, "verify-plan", "", "compare what would be deleted now with a `file` written by --plan, without deleting anything")
+	flag.IntVar(&opts.MaxDrift, "max-drift", 0, "with --verify-plan, exit with code 6 when more than `N` notifications drifted")
+	flag.BoolVar(&opts.ResumeFailed, "resume-failed", false, "only retry the deletions that failed in the previous run")
+	flag.StringVar(&opts.Hook, "hook", "", "shell command to run after deletions, gets GH_FLUSH_REPO, GH_FLUSH_TITLE and GH_FLUSH_ID")
+	flag.StringVar(&opts.HookTiming, "hook-timing", HookEach, "when to run --hook: `each` deletion, or once at the end with GH_FLUSH_PROCESSED and GH_FLUSH_DELETED")
+	flag.DurationVar(&opts.HookTimeout, "hook-timeout", 10*time.Second, "kill --hook commands running longer than this")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "`gh flush` deletes all GitHub notifications that are from bots,\nand/or are about closed pull requests\n\nUsage:\n")
 		flag.PrintDefaults()
@@ -50,54 +183,373 @@ func parseOptions() *Options {
 	args := flag.Args()
 	if len(args) != 0 {
 		flag.Usage()
-		msg := fmt.Sprintf("unexpected arguments: %v", args)
-		panic(msg)
+		exitWithError(fmt.Errorf("unexpected arguments: %v", args))
+	}
+	config, err := loadConfig()
+	if err != nil {
+		exitWithError(err)
+	}
+	dryRunFlag := flag.CommandLine.Changed("dry-run")
+	if err := applyDefaults(flag.CommandLine, config); err != nil {
+		exitWithError(err)
+	}
+	if opts.DryRun && !dryRunFlag {
+		opts.dryRunBy = "the config file"
+		if _, ok := os.LookupEnv(flagEnv("dry-run")); ok {
+			opts.dryRunBy = flagEnv("dry-run")
+		}
+	}
+	// like gh, --format also takes a template
+	if strings.Contains(opts.Format, "{{") && opts.Template == "" {
+		opts.Template, opts.Format = opts.Format, FormatTable
+	}
+	if err := validateOptions(opts); err != nil {
+		exitWithError(err)
+	}
+	if os.Getenv(ReadOnlyEnv) != "" && os.Getenv(ReadOnlyEnv) != "0" {
+		opts.ReadOnly = true
+		opts.DryRun = true
+	}
+	if by := opts.dryRunFlag(); by != "" {
+		opts.DryRun = true
+		opts.dryRunBy = by
+	}
+	if opts.Plan || opts.VerifyPlan != "" {
+		// the plan gets reviewed instead
+		opts.ConfirmPerRepo = false
+		opts.InteractiveConfirm = false
+		opts.Review = false
+		opts.Select = false
+	}
+	if opts.DryRun {
+		// nothing to undo
+		opts.UndoWindow = 0
+	}
+
+	if *before != "" {
+		t, err := parseTime(*before)
+		if err != nil {
+			exitWithError(fmt.Errorf("invalid --before: %w", err))
+		}
+		opts.Before = t
+	}
+	if *since != "" {
+		t, err := parseTime(*since)
+		if err != nil {
+			exitWithError(fmt.Errorf("invalid --since: %w", err))
+		}
+		opts.Since = t
+		if !opts.Before.IsZero() && !opts.Since.Before(opts.Before) {
+			exitWithError(errors.New("--since must be before --before"))
+		}
+	}
+
+	if opts.Incremental {
+		if !flag.CommandLine.Changed("halt-after") {
+			opts.HaltAfter = 0
+		}
+		if opts.offsets, err = loadOffsets(); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	if opts.Subject != "" {
+		ref, err := parseSubjectRef(opts.Subject)
+		if err != nil {
+			exitWithError(err)
+		}
+		opts.subject = ref
+		// look for it in the repository's notifications, however far back
+		opts.Repos = []string{ref.repo}
+		opts.HaltAfter = 0
+	}
+
+	opts.reasonLabels = config.Reasons
+	if *listFlagValues != "" {
+		if err := printFlagValues(config, *listFlagValues); err != nil {
+			exitWithError(err)
+		}
+		os.Exit(0)
+	}
+	if *listQueries {
+		config.printQueries()
+		os.Exit(0)
+	}
+	if opts.Rules {
+		if opts.actionRules, err = config.rules(); err != nil {
+			exitWithError(err)
+		}
+		if len(opts.actionRules) == 0 {
+			fileName, _ := configFile()
+			exitWithError(fmt.Errorf("--rules given, but %s defines no rules", fileName))
+		}
+	}
+	for _, name := range opts.Queries {
+		rule, err := config.query(name)
+		if err != nil {
+			exitWithError(err)
+		}
+		opts.deleteRules = append(opts.deleteRules, rule)
+	}
+	for _, expr := range opts.DeleteWhen {
+		rule, err := parseDeleteRule(expr)
+		if err != nil {
+			exitWithError(err)
+		}
+		opts.deleteRules = append(opts.deleteRules, rule)
+	}
+	if opts.Template != "" {
+		tmpl, err := parseOutputTemplate(opts.Template)
+		if err != nil {
+			exitWithError(err)
+		}
+		opts.template = tmpl
+	}
+
+	patternFiles := []struct {
+		flagName string
+		fileName string
+		patterns *[]string
+	}{
+		{"repo", *repoFile, &opts.Repos},
+		{"exclude-repo", *excludeRepoFile, &opts.ExcludeRepos},
+		{"protect-repo", *protectRepoFile, &opts.ProtectRepos},
+	}
+	orgFlags := []struct {
+		flagName string
+		orgs     []string
+		patterns *[]string
+	}{
+		{"org", *orgs, &opts.Repos},
+		{"exclude-org", *excludeOrgs, &opts.ExcludeRepos},
+	}
+	for _, of := range orgFlags {
+		for _, org := range of.orgs {
+			if org == "" || strings.ContainsAny(org, "/*?[") {
+				exitWithError(fmt.Errorf("invalid --%s %q, expected an organization or user name", of.flagName, org))
+			}
+			*of.patterns = append(*of.patterns, org+"/*")
+		}
+	}
+	for _, pf := range patternFiles {
+		if pf.fileName != "" {
+			patterns, err := readPatternFile(pf.fileName)
+			if err != nil {
+				exitWithError(err)
+			}
+			*pf.patterns = append(*pf.patterns, patterns...)
+		}
+		if err := validateRepoPatterns(pf.flagName, *pf.patterns); err != nil {
+			exitWithError(err)
+		}
 	}
 	return opts
 }
 
-func (client *Client) FetchNotifications() {
-	requestPath := "notifications?all=true"
+// parseTime accepts RFC 3339 timestamps and plain dates.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 timestamp nor a YYYY-MM-DD date", s)
+	}
+	return t, nil
+}
+
+func exitWithError(err error) {
+	fmt.Fprintln(os.Stderr, "gh flush:", err)
+	os.Exit(1)
+}
+
+func (client *Client) FetchNotifications() error {
+	if client.opts.ResumeFailed {
+		notifications, err := loadFailures()
+		if err != nil {
+			return err
+		}
+		client.notifications = client.opts.filterRepos(notifications)
+		client.numFetched.Store(int64(len(client.notifications)))
+		client.fetchDone.Store(true)
+		return nil
+	}
+	if client.opts.ApplyPlan != "" {
+		notifications, err := loadPlan(client.opts.ApplyPlan)
+		if err != nil {
+			return err
+		}
+		client.notifications = client.opts.filterRepos(notifications)
+		client.numFetched.Store(int64(len(client.notifications)))
+		client.fetchDone.Store(true)
+		return nil
+	}
+	if client.streaming() {
+		return client.startStream()
+	}
+
+	notifications := []Notification{}
+	for _, host := range client.hosts() {
+		err := client.fetchNotifications(host, func(page []Notification) {
+			notifications = append(notifications, page...)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	client.notifications, client.duplicates = dedupe(notifications)
+	if client.opts.Estimate > 0 {
+		client.sampleForEstimate()
+	}
+	client.numFetched.Store(int64(len(client.notifications)))
+	client.fetchDone.Store(true)
+	if !client.opts.RESTLookups && !client.opts.ListTypes {
+		client.prefetchPullRequests(client.notifications)
+	}
+
+	if client.opts.DumpNotifications != "" {
+		if err := client.dumpNotifications(client.opts.DumpNotifications); err != nil {
+			return err
+		}
+	}
+	if client.opts.subject != nil && len(notifications) == 0 {
+		return fmt.Errorf("no notification found for %s, it may have been deleted already", client.opts.subject)
+	}
+	return nil
+}
+
+// fetchNotifications loads the notifications of one host and hands them on
+// page by page.
+func (client *Client) fetchNotifications(host string, emit func([]Notification)) error {
+	query := url.Values{"all": {"true"}, "per_page": {strconv.Itoa(client.opts.PerPage)}}
+	if !client.opts.Before.IsZero() {
+		query.Set("before", client.opts.Before.Format(time.RFC3339))
+	}
+	since := client.fetchSince(host)
+	if client.opts.Since.After(since) {
+		since = client.opts.Since
+	}
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339))
+	}
+	if client.opts.Participating {
+		query.Set("participating", "true")
+	}
+	requestPath := client.opts.notificationsEndpoint() + "?" + query.Encode()
 	page := 1
-	ghApiClient, err := api.DefaultRESTClient()
+	ghApiClient, err := client.restClient(host)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if client.opts.Team != "" {
+		if err := client.loadTeamRepos(ghApiClient); err != nil {
+			return err
+		}
 	}
 
 	readStreak := 0
-	notifications := []Notification{}
 
 loadNotifications:
 	for {
-		response, err := ghApiClient.Request(http.MethodGet, requestPath, nil)
-		notificationBatch := []Notification{}
-		decoder := json.NewDecoder(response.Body)
-		err = decoder.Decode(&notificationBatch)
+		var response *http.Response
+		_, err := client.throttled(func() (http.Header, error) {
+			var err error
+			response, err = ghApiClient.Request(http.MethodGet, requestPath, nil)
+			if err != nil {
+				return nil, err
+			}
+			return response.Header, nil
+		})
 		if err != nil {
-			panic(err)
+			return fmt.Errorf("cannot fetch notifications: %w", client.recordAPIError(endpointNotifications, err))
+		}
+		notificationBatch, err := decodeNotifications(response.Body)
+		if err != nil {
+			response.Body.Close()
+			return err
 		}
 		if err := response.Body.Close(); err != nil {
 			fmt.Println(err)
 		}
+		notifications := []Notification{}
+		halted := false
 		for _, notification := range notificationBatch {
 			if notification.Unread {
 				readStreak = 0
 			} else {
 				readStreak++
 				if client.opts.HaltAfter > 0 && readStreak >= client.opts.HaltAfter {
-					break loadNotifications
+					client.truncated = fmt.Sprintf("stopped after %d read notifications in a row (--halt-after)", readStreak)
+					halted = true
+					break
 				}
 			}
+			if !client.opts.includeRepo(notification.Repository.FullName) && !client.opts.maybeRenamed(notification) {
+				continue
+			}
+			if !client.inScope(ghApiClient, notification.Repository.FullName) {
+				continue
+			}
+			if client.opts.subject != nil && !client.opts.subject.matches(notification) {
+				continue
+			}
+			if client.alreadyDeleted[notification.Id] {
+				continue
+			}
 			notifications = append(notifications, notification)
 		}
+		emit(notifications)
+		if halted {
+			break loadNotifications
+		}
 
 		var hasNextPage bool
-		if requestPath, hasNextPage = findNextPage(response); !hasNextPage {
+		if response.Header.Get("Link") != "" {
+			requestPath, hasNextPage = findNextPage(response)
+		} else if len(notificationBatch) == client.opts.PerPage {
+			// some proxies strip the Link header, count pages until one
+			// comes back short
+			query.Set("page", strconv.Itoa(page+1))
+			requestPath, hasNextPage = client.opts.notificationsEndpoint()+"?"+query.Encode(), true
+		}
+		if !hasNextPage {
+			break loadNotifications
+		}
+		if client.opts.MaxPages > 0 && page >= client.opts.MaxPages {
+			client.truncated = fmt.Sprintf("stopped after %d pages (--max-pages)", page)
 			break loadNotifications
 		}
 		page++
 	}
-	client.notifications = notifications
+	return nil
+}
+
+// Truncated explains why fetching stopped before the last page, or returns
+// an empty string if all notifications were fetched.
+func (client *Client) Truncated() string {
+	return client.truncated
+}
+
+// decodeNotifications reads a page of notifications. Some failures come
+// back as an error object instead of an array, their message is returned
+// as the error.
+func decodeNotifications(body io.Reader) ([]Notification, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch notifications: %w", err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		apiErr := struct{ Message string }{}
+		if err := json.Unmarshal(trimmed, &apiErr); err == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("GitHub returned an error instead of notifications: %s", apiErr.Message)
+		}
+		return nil, errors.New("GitHub returned an object instead of a list of notifications")
+	}
+	notifications := []Notification{}
+	if err := json.Unmarshal(data, &notifications); err != nil {
+		return nil, fmt.Errorf("cannot decode notifications: %w", err)
+	}
+	return notifications, nil
 }
 
 var linkRE = regexp.MustCompile(`<([^>]+)>;\s*rel="([^"]+)"`)
@@ -111,13 +563,22 @@ func findNextPage(response *http.Response) (string, bool) {
 	return "", false
 }
 
+// NotificationCount is how many notifications were fetched so far, see
+// FetchDone.
 func (client *Client) NotificationCount() int {
-	return len(client.notifications)
+	return int(client.numFetched.Load())
 }
 
 func (client *Client) ProcessNotifications() {
-	client.wgFetcher.Add(client.opts.NumWorkers)
-	client.wgDeleter.Add(client.opts.NumWorkers)
+	if client.streamed {
+		client.startWorkers()
+		return
+	}
+	if len(client.notifications) == 0 {
+		// nothing to do, don't bother starting workers
+		client.finish()
+		return
+	}
 
 	go func() {
 		defer close(client.input)
@@ -125,14 +586,44 @@ func (client *Client) ProcessNotifications() {
 			client.input <- n
 		}
 	}()
+	client.startWorkers()
+}
 
+func (client *Client) startWorkers() {
+	client.wgFetcher.Add(client.opts.NumWorkers)
+	client.wgDeleter.Add(client.opts.NumWorkers)
 	for i := 0; i < client.opts.NumWorkers; i++ {
 		go client.tagNotifications()
 		go client.deleteNotifications()
 	}
 
 	go func() { defer close(client.statuses); client.wgFetcher.Wait() }()
-	go func() { defer close(client.results); client.wgDeleter.Wait() }()
+	go func() {
+		client.wgDeleter.Wait()
+		client.finish()
+	}()
+}
+
+// finish wraps up once all workers are done and closes the results.
+func (client *Client) finish() {
+	defer close(client.results)
+	client.applyHeld()
+	if len(client.pending) > 0 {
+		// the rest happens in ApplyPending
+		return
+	}
+	client.wrapUp()
+}
+
+// wrapUp does the bookkeeping once nothing is left to delete.
+func (client *Client) wrapUp() {
+	client.saveFailures()
+	client.writeReportFile()
+	client.clearCommitLog()
+	client.compareWithLastRun()
+	client.saveOffsets()
+	client.purgeBackups()
+	client.runEndHook()
 }
 
 func (client *Client) GetNotificationResult() (NotificationResult, bool) {
@@ -140,30 +631,130 @@ func (client *Client) GetNotificationResult() (NotificationResult, bool) {
 	return result, ok
 }
 
+// GetNotificationResults waits for a result and then takes up to max results
+// that are ready without waiting. It returns false once all results have
+// been read.
+func (client *Client) GetNotificationResults(max int) ([]NotificationResult, bool) {
+	result, ok := <-client.results
+	if !ok {
+		return nil, false
+	}
+	results := []NotificationResult{result}
+	for len(results) < max {
+		select {
+		case result, ok := <-client.results:
+			if !ok {
+				return results, true
+			}
+			results = append(results, result)
+		default:
+			return results, true
+		}
+	}
+	return results, true
+}
+
 func (client *Client) tagNotifications() {
 	defer client.wgFetcher.Done()
 
-	ghApiClient, err := api.DefaultRESTClient()
-	if err != nil {
-		panic(err)
-	}
+	var err error
 	for notification := range client.input {
 		result := NotificationResult{Notification: notification}
+		ghApiClient, clientErr := client.restClient(notification.Host())
+		if clientErr != nil {
+			result.Err = clientErr
+			client.recordSkipped()
+			client.statuses <- result
+			continue
+		}
+
+		if client.opts.ApplyPlan != "" {
+			// only apply the plan to threads that still exist, and tag them
+			// afresh so that protections added since the plan hold
+			fresh := Notification{}
+			if err := client.get(ghApiClient, notification.Url, &fresh); err != nil {
+				result.Err = client.recordAPIError(endpointThreadCheck, err)
+				client.recordSkipped()
+				client.statuses <- result
+				continue
+			}
+			notification = fresh
+			result.Notification = fresh
+		}
 
-		if !notification.Unread && !client.opts.SkipReadNotifications {
+		if read(notification) && !client.opts.SkipReadNotifications {
 			result.Read = true
 		}
+		result.NewActivity = newActivity(notification)
+		result.Commit = notification.Subject.Type == "Commit"
+		if client.opts.ShowSubscription {
+			result.Subscription, err = client.subscription(ghApiClient, notification)
+			if err != nil {
+				client.recordAPIError(endpointSubscription, err)
+			}
+		}
 
 		if notification.Subject.Type == "PullRequest" {
 
-			pr := new(PullRequest)
-			err := ghApiClient.Get(notification.Subject.Url, &pr)
+			pr := client.prefetchedPullRequest(notification.Subject.Url)
+			var err error
+			if pr == nil {
+				pr = new(PullRequest)
+				err = client.timed(&result.FetchTime, func() error {
+					return client.get(ghApiClient, notification.Subject.Url, &pr)
+				})
+			}
+			if err != nil && !client.opts.includeRepo(notification.Repository.FullName) {
+				// fetched in case the repository was renamed into --repo
+				client.numFetched.Add(-1)
+				continue
+			}
 			if err != nil {
-				panic(err)
+				result.Err = client.recordAPIError(endpointPullRequest, err)
+				client.recordSkipped()
+				client.statuses <- result
+				continue
 			}
 			result.PR = pr
 			result.BotPR = from_a_bot(pr)
 			result.ClosedPR = closedPR(pr)
+			result.MergedPR = pr.Merged
+			result.StaleDraft = pr.Draft && time.Since(pr.UpdatedAt) > client.opts.StaleDraftAge
+			if client.opts.FlushOwnMerged || client.opts.SkipChangesRequested {
+				// failing to look up the user is recorded once
+				result.OwnPR, _ = client.ownPR(ghApiClient, notification.Host(), pr)
+			}
+			if client.opts.SkipChangesRequested && result.OwnPR {
+				result.ReviewState, err = client.reviewState(ghApiClient, notification.Subject.Url)
+				if err != nil {
+					client.recordAPIError(endpointReviews, err)
+				}
+			}
+			renamed(&result)
+			if !client.opts.includeRepo(notification.Repository.FullName) && !client.opts.includeRepo(result.Notification.Repository.FullName) {
+				// neither the old nor the current name is in --repo
+				client.numFetched.Add(-1)
+				continue
+			}
+		}
+		if notification.Subject.Type == "Issue" && (!client.opts.SkipClosedIssues || client.opts.FlushStateChanges || len(client.opts.ProtectLabels) > 0 || client.opts.ActiveThreshold > 0) {
+			err = client.timed(&result.FetchTime, func() (err error) {
+				result.Issue, err = client.issue(ghApiClient, notification.Subject.Url)
+				return err
+			})
+			if err != nil {
+				client.recordAPIError(endpointIssue, err)
+			}
+			result.ClosedIssue = result.Issue != nil && result.Issue.State == "closed"
+		}
+		if client.opts.FlushStateChanges && notification.Reason == "state_change" {
+			result.ObsoleteStateChange = result.ClosedPR || result.Issue != nil && result.Issue.State == "closed"
+		}
+		if client.opts.SkipLastCommentedByMe && (notification.Subject.Type == "PullRequest" || notification.Subject.Type == "Issue") {
+			result.LastCommentMine, err = client.lastCommentMine(ghApiClient, notification)
+			if err != nil {
+				client.recordAPIError(endpointComment, err)
+			}
 		}
 		client.statuses <- result
 	}
@@ -172,6 +763,11 @@ func (client *Client) tagNotifications() {
 func read(notification Notification) bool {
 	return !notification.Unread
 }
+
+// newActivity reports whether a read thread was updated after it was read.
+func newActivity(notification Notification) bool {
+	return read(notification) && notification.LastReadAt != nil && notification.UpdatedAt.After(*notification.LastReadAt)
+}
 func from_a_bot(pullRequest *PullRequest) bool {
 	return pullRequest.User.Type == "Bot"
 }
@@ -182,58 +778,180 @@ func closedPR(pullRequest *PullRequest) bool {
 
 func (client *Client) deleteNotifications() {
 	defer client.wgDeleter.Done()
-	ghApiClient, err := api.DefaultRESTClient()
-	if err != nil {
-		panic(err)
+	for status := range client.statuses {
+		if status.Err == nil {
+			client.decide(&status)
+		}
+		if status.acted() {
+			client.numMatched.Add(1)
+		}
+		if status.acted() && client.holdMatches() {
+			client.hold(status)
+			continue
+		}
+		ghApiClient, clientErr := client.restClient(status.Notification.Host())
+		if clientErr != nil {
+			fail(&status, clientErr)
+		}
+		client.apply(ghApiClient, &status)
+		client.send(status)
 	}
+}
 
-	for status := range client.statuses {
+// decide sets whether a notification should be deleted.
+func (client *Client) decide(status *NotificationResult) {
+	if len(client.opts.actionRules) > 0 {
+		client.applyRules(status)
+	} else if len(client.opts.deleteRules) > 0 {
+		for _, rule := range client.opts.deleteRules {
+			if rule.matches(*status) {
+				status.Deleted = true
+			}
+		}
+	} else if client.opts.FlushOwnMerged {
+		status.Deleted = status.OwnPR && status.MergedPR
+	} else {
 		if status.BotPR && !client.opts.SkipPRsFromBots {
 			status.Deleted = true
 		}
-		if status.ClosedPR && !client.opts.SkipClosedPRs {
+		if status.ClosedPR && !client.opts.SkipClosedPRs && (status.MergedPR || !client.opts.SkipUnmergedClosed) {
+			status.Deleted = true
+		}
+		if status.ClosedIssue && !client.opts.SkipClosedIssues {
 			status.Deleted = true
 		}
 		if status.Read && !client.opts.SkipReadNotifications {
 			status.Deleted = true
 		}
-
-		if status.Deleted && !client.opts.DryRun {
-			err := ghApiClient.Delete(status.Notification.Url, nil)
-			if err != nil {
-				panic(err)
-			}
+	}
+	if client.opts.ResumeFailed || client.opts.ApplyPlan != "" || client.opts.subject != nil {
+		// these name the threads to delete, the guards below still apply
+		status.Deleted = true
+	}
+	// a matching rule of the config file has the last word
+	if status.Rule == "" {
+		if client.opts.FlushStaleDrafts && status.StaleDraft {
+			status.Deleted = true
+		}
+		if client.opts.FlushStateChanges && status.ObsoleteStateChange {
+			status.Deleted = true
 		}
+		if client.opts.FlushCommitComments && status.Commit && time.Since(status.Notification.UpdatedAt) > client.opts.CommitCommentAge {
+			status.Deleted = true
+		}
+		if client.opts.OlderThan > 0 && time.Since(status.Notification.UpdatedAt) > client.opts.OlderThan {
+			status.Deleted = true
+			status.Old = true
+		}
+	}
+	if status.RenamedFrom != "" && !client.opts.includeRepo(status.Notification.Repository.FullName) {
+		status.Deleted = false
+		status.Action = ""
+		status.Excluded = true
+	}
+	if status.acted() && (matchRepo(client.opts.ProtectRepos, status.Notification.Repository.FullName) ||
+		status.RenamedFrom != "" && matchRepo(client.opts.ProtectRepos, status.RenamedFrom)) {
+		status.protect()
+	}
+	if status.acted() && status.hasLabel(client.opts.ProtectLabels) {
+		status.protect()
+		status.Labeled = true
+	}
+	if status.acted() && client.opts.UnreadSinceRead && status.NewActivity {
+		status.protect()
+	}
+	if status.acted() && client.opts.SkipChangesRequested && status.ReviewState == ReviewChangesRequested {
+		status.protect()
+	}
+	if status.acted() && client.opts.SkipLastCommentedByMe && status.LastCommentMine {
+		status.protect()
+	}
+	if status.acted() && client.opts.NewerThan > 0 && time.Since(status.Notification.UpdatedAt) < client.opts.NewerThan {
+		status.protect()
+		status.Fresh = true
+	}
+	if status.acted() && client.opts.KeepMentionedWithin > 0 && status.Notification.Reason == "mention" &&
+		time.Since(status.Notification.UpdatedAt) < client.opts.KeepMentionedWithin {
+		status.protect()
+		status.RecentMention = true
+	}
+	if status.acted() && client.opts.ActiveThreshold > 0 && status.commentCount() > client.opts.ActiveThreshold {
+		status.protect()
+		status.Active = true
+	}
+	// rules of the config file pick their own action
+	if status.Deleted && status.Rule == "" && client.opts.Mode == ModeRead {
+		status.Deleted = false
+		status.Action = ActionMarkRead
+	}
+}
 
-		client.results <- status
+// commentCount is the number of comments on the pull request or issue of a
+// notification, as far as it was looked up.
+func (res *NotificationResult) commentCount() int {
+	switch {
+	case res.PR != nil:
+		return res.PR.CommentCount()
+	case res.Issue != nil:
+		return res.Issue.Comments
 	}
+	return 0
 }
 
-func (client *Client) PrintResults() {
-	fmt.Println("Time                \tReason [Repo] Title")
+// acted reports whether a notification is going to be deleted, marked as
+// read or unsubscribed from.
+func (res *NotificationResult) acted() bool {
+	return res.Deleted || res.Action != ""
+}
 
-	result, ok := client.GetNotificationResult()
-	for ok {
-		reason := ""
-		if result.Deleted {
-			reason += Deleted
-		}
-		if result.Read {
-			reason += Read
-		}
-		if result.ClosedPR {
-			reason += ClosedPR
-		}
-		if result.BotPR {
-			reason += BotPR
-		}
+// protect holds a notification back from whatever was decided for it.
+func (res *NotificationResult) protect() {
+	res.cancel()
+	res.Protected = true
+}
 
-		if reason != "" {
-			reason += " "
+// cancel keeps a notification that was going to be deleted, marked as read
+// or unsubscribed from.
+func (res *NotificationResult) cancel() {
+	res.Deleted = false
+	res.Action = ""
+}
+
+// apply deletes a notification unless it was only simulated.
+func (client *Client) apply(ghApiClient *api.RESTClient, status *NotificationResult) {
+	if status.Deleted && client.opts.CheckPermissions {
+		client.checkPermission(ghApiClient, status)
+	}
+	if status.Deleted && !client.opts.DryRun && !status.Simulated {
+		client.deletePacer.wait()
+		client.workerGate.acquire()
+		var header http.Header
+		err := client.timed(&status.DeleteTime, func() (err error) {
+			header, err = client.mutate(ghApiClient, http.MethodDelete, status.Notification.Url, nil)
+			return err
+		})
+		client.workerGate.release(header, err)
+		if err != nil {
+			status.Deleted = false
+			status.Err = client.recordAPIError(endpointThread, err)
+			client.recordFailure(*status)
+		} else {
+			client.logCommit(status.Notification)
+			client.backup(status.Notification)
+			if client.opts.Unsubscribe {
+				status.Unsubscribed = client.unsubscribe(ghApiClient, status) == nil
+			}
+			client.runDeleteHook(status.Notification)
 		}
+	}
+	if status.Action != "" && !client.opts.DryRun && !status.Simulated {
+		client.deletePacer.wait()
+		client.act(ghApiClient, status)
+	}
 
-		ts := result.Notification.UpdatedAt.Format(time.RFC3339)
-		fmt.Printf("%s\t%s[%s] %s\n", ts, reason, result.Notification.Repository.FullName, result.Notification.Subject.Title)
-		result, ok = client.GetNotificationResult()
+	client.tallyEstimate(*status)
+	client.numProcessed.Add(1)
+	if status.Deleted {
+		client.numDeleted.Add(1)
 	}
 }
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// fakeClient returns a client that talks to handler instead of GitHub. The
+// handler sees the paths below /api/v3, as for GitHub Enterprise Server.
+func fakeClient(t *testing.T, opts *Options, handler http.Handler) (*Client, string) {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.StripPrefix("/api/v3", handler))
+	t.Cleanup(srv.Close)
+	host := strings.TrimPrefix(srv.URL, "https://")
+	ghApiClient, err := api.NewRESTClient(api.ClientOptions{Host: host, AuthToken: "token", Transport: srv.Client().Transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Hostnames = []string{host}
+	if opts.PerPage == 0 {
+		opts.PerPage = 100
+	}
+	if opts.NumWorkers == 0 {
+		opts.NumWorkers = 1
+	}
+	client := &Client{
+		opts:        opts,
+		input:       make(chan Notification, 100),
+		statuses:    make(chan NotificationResult, 100),
+		results:     make(chan NotificationResult, 100),
+		wgFetcher:   new(sync.WaitGroup),
+		wgDeleter:   new(sync.WaitGroup),
+		restClients: map[string]*api.RESTClient{host: ghApiClient},
+	}
+	return client, "https://" + host + "/api/v3/"
+}
+
+// fetchAll runs fetchNotifications against the fake host and collects what
+// it hands on.
+func fetchAll(t *testing.T, client *Client) []Notification {
+	t.Helper()
+	var fetched []Notification
+	err := client.fetchNotifications(client.hosts()[0], func(page []Notification) {
+		fetched = append(fetched, page...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fetched
+}
+
+func TestFetchWithoutLinkHeader(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Query().Get("page") {
+		case "":
+			fmt.Fprint(w, `[{"id": "1", "unread": true}, {"id": "2", "unread": true}]`)
+		case "2":
+			fmt.Fprint(w, `[{"id": "3", "unread": true}]`)
+		default:
+			t.Errorf("fetched page %s after a short page", r.URL.Query().Get("page"))
+			fmt.Fprint(w, `[]`)
+		}
+	})
+	client, _ := fakeClient(t, &Options{PerPage: 2}, handler)
+
+	if fetched := fetchAll(t, client); len(fetched) != 3 {
+		t.Errorf("fetched %d notifications, want 3", len(fetched))
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+}
+
+func TestFindNextPage(t *testing.T) {
+	tests := []struct {
+		link     string
+		wantPath string
+		wantNext bool
+	}{
+		{"", "", false},
+		{`<https://api.github.com/notifications?page=2>; rel="next", <https://api.github.com/notifications?page=5>; rel="last"`,
+			"https://api.github.com/notifications?page=2", true},
+		{`<https://api.github.com/notifications?page=4>; rel="prev", <https://api.github.com/notifications?page=1>; rel="first"`,
+			"", false},
+		{`<https://api.github.com/notifications?page=1>; rel="first",<https://api.github.com/notifications?page=3>;rel="next"`,
+			"https://api.github.com/notifications?page=3", true},
+	}
+	for _, tt := range tests {
+		response := &http.Response{Header: http.Header{"Link": {tt.link}}}
+		path, next := findNextPage(response)
+		if path != tt.wantPath || next != tt.wantNext {
+			t.Errorf("findNextPage(%q) = %q, %v, want %q, %v", tt.link, path, next, tt.wantPath, tt.wantNext)
+		}
+	}
+}
+
+func TestDecodeNotifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantLen int
+		wantErr string
+	}{
+		{"list", `[{"id": "1"}, {"id": "2"}]`, 2, ""},
+		{"empty list", ` [] `, 0, ""},
+		{"error object", `{"message": "Bad credentials", "documentation_url": "https://docs.github.com/rest"}`, 0, "Bad credentials"},
+		{"other object", `{"id": "1"}`, 0, "an object instead of a list"},
+		{"garbage", `<html>502 Bad Gateway</html>`, 0, "cannot decode notifications"},
+		{"truncated", `[{"id": "1"`, 0, "cannot decode notifications"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications, err := decodeNotifications(strings.NewReader(tt.body))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("decodeNotifications() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("decodeNotifications() = %v, want an error about %s", err, tt.wantErr)
+			}
+			if len(notifications) != tt.wantLen {
+				t.Errorf("decoded %d notifications, want %d", len(notifications), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestFetchMaxPages(t *testing.T) {
+	requests := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := requests
+		w.Header().Set("Link", fmt.Sprintf(`<https://%s/api/v3/notifications?page=%d>; rel="next"`, r.Host, page+1))
+		fmt.Fprintf(w, `[{"id": "%d", "unread": true}]`, page)
+	})
+	client, _ := fakeClient(t, &Options{MaxPages: 2}, handler)
+
+	if fetched := fetchAll(t, client); len(fetched) != 2 {
+		t.Errorf("fetched %d notifications, want 2", len(fetched))
+	}
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+	if client.Truncated() == "" {
+		t.Error("Truncated() is empty, want the reason fetching stopped")
+	}
+}
+
+func TestProcessNoNotifications(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	client := &Client{
+		opts:      &Options{NumWorkers: 4},
+		input:     make(chan Notification),
+		statuses:  make(chan NotificationResult),
+		results:   make(chan NotificationResult),
+		wgFetcher: new(sync.WaitGroup),
+		wgDeleter: new(sync.WaitGroup),
+	}
+	before := runtime.NumGoroutine()
+
+	client.ProcessNotifications()
+
+	if _, ok := client.GetNotificationResult(); ok {
+		t.Error("GetNotificationResult() returned a result, want none")
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines were started, want none", after-before)
+	}
+	if !client.NothingMatched() {
+		t.Error("NothingMatched() = false, want true")
+	}
+}
+
+func TestFlushCommitComments(t *testing.T) {
+	daysAgo := func(days int) string {
+		return time.Now().AddDate(0, 0, -days).UTC().Format(time.RFC3339)
+	}
+	tests := []struct {
+		name        string
+		flush       bool
+		subjectType string
+		updated     string
+		wantDeleted bool
+	}{
+		{"quiet commit", true, "Commit", daysAgo(40), true},
+		{"recent commit", true, "Commit", daysAgo(2), false},
+		{"without --flush-commit-comments", false, "Commit", daysAgo(40), false},
+		{"quiet release", true, "Release", daysAgo(40), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			var api string
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `[{"id": "1", "url": "%snotifications/threads/1", "unread": true, "updated_at": %q,
+					"repository": {"full_name": "cli/cli"}, "subject": {"type": %q, "title": "Fix typo"}}]`,
+					api, tt.updated, tt.subjectType)
+			})
+			opts := &Options{DryRun: true, OrderBy: OrderUpdated, FlushCommitComments: tt.flush, CommitCommentAge: 30 * day}
+			client, api := fakeClient(t, opts, handler)
+
+			for _, notification := range fetchAll(t, client) {
+				client.input <- notification
+			}
+			close(client.input)
+			client.wgFetcher.Add(1)
+			client.tagNotifications()
+			close(client.statuses)
+			client.wgDeleter.Add(1)
+			client.deleteNotifications()
+			client.finish()
+
+			result, ok := <-client.results
+			if !ok {
+				t.Fatal("no result")
+			}
+			if result.Commit != (tt.subjectType == "Commit") {
+				t.Errorf("Commit = %v for a %s", result.Commit, tt.subjectType)
+			}
+			if result.Deleted != tt.wantDeleted {
+				t.Errorf("Deleted = %v, want %v", result.Deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestActiveThreshold(t *testing.T) {
+	tests := []struct {
+		name       string
+		pr         *PullRequest
+		issue      *Issue
+		wantActive bool
+	}{
+		{"busy issue", nil, &Issue{Comments: 6}, true},
+		{"quiet issue", nil, &Issue{Comments: 5}, false},
+		{"busy pull request", &PullRequest{Comments: 2, ReviewComments: 4}, nil, true},
+		{"quiet pull request", &PullRequest{Comments: 2, ReviewComments: 1}, nil, false},
+		{"not looked up", nil, nil, false},
+	}
+	for _, tt := range tests {
+		client := &Client{opts: &Options{ActiveThreshold: 5}}
+		status := NotificationResult{Read: true, PR: tt.pr, Issue: tt.issue}
+		client.decide(&status)
+		if status.Active != tt.wantActive || status.Deleted == tt.wantActive {
+			t.Errorf("%s: Active = %v, Deleted = %v, want it active: %v", tt.name, status.Active, status.Deleted, tt.wantActive)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// lastCommentMine reports whether the authenticated user wrote the latest
+// comment on the subject of a notification. It is cached per subject.
+func (client *Client) lastCommentMine(ghApiClient *api.RESTClient, notification Notification) (bool, error) {
+	subject := notification.Subject.Url
+	client.mu.Lock()
+	author, ok := client.lastCommenters[subject]
+	client.mu.Unlock()
+	if !ok {
+		// without comments, the latest comment URL points at the subject
+		// itself
+		if commentUrl := notification.Subject.LatestCommentUrl; strings.Contains(commentUrl, "/comments/") {
+			comment := struct{ User struct{ Login string } }{}
+			if err := client.get(ghApiClient, commentUrl, &comment); err != nil {
+				return false, err
+			}
+			author = comment.User.Login
+		}
+		client.mu.Lock()
+		if client.lastCommenters == nil {
+			client.lastCommenters = map[string]string{}
+		}
+		client.lastCommenters[subject] = author
+		client.mu.Unlock()
+	}
+	if author == "" {
+		return false, nil
+	}
+	login, err := client.currentUser(ghApiClient, notification.Host())
+	return err == nil && strings.EqualFold(author, login), err
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The commit log records every deletion as it happens, so that a run that
+// crashed can be continued with --continue. It is removed once a run
+// completes.
+
+func commitLogFile() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "commit.log"), nil
+}
+
+// checkCommitLog reports a commit log left behind by an interrupted run, or
+// with --continue loads the threads it already deleted.
+func (client *Client) checkCommitLog() {
+	fileName, err := commitLogFile()
+	if err != nil {
+		return
+	}
+	f, err := os.Open(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot read the commit log:", err)
+		return
+	}
+	defer f.Close()
+
+	deleted := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if id, _, _ := strings.Cut(scanner.Text(), "\t"); id != "" {
+			deleted[id] = true
+		}
+	}
+	if !client.opts.Continue {
+		fmt.Fprintf(os.Stderr, "gh flush: an earlier run was interrupted after deleting %d notifications, pass --continue to skip them\n", len(deleted))
+		return
+	}
+	client.alreadyDeleted = deleted
+}
+
+// logCommit appends a deleted thread to the commit log and syncs it to
+// disk.
+func (client *Client) logCommit(notification Notification) {
+	client.commitMu.Lock()
+	defer client.commitMu.Unlock()
+	if client.commitLog == nil {
+		fileName, err := commitLogFile()
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(fileName), 0o755)
+		}
+		if err == nil {
+			client.commitLog, err = os.OpenFile(fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush: cannot write the commit log:", err)
+			return
+		}
+	}
+	_, err := fmt.Fprintf(client.commitLog, "%s\t%s\n", notification.Id, time.Now().UTC().Format(time.RFC3339))
+	if err == nil {
+		err = client.commitLog.Sync()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write the commit log:", err)
+	}
+}
+
+// clearCommitLog removes the commit log once a run completed.
+func (client *Client) clearCommitLog() {
+	if client.opts.DryRun {
+		return
+	}
+	client.commitMu.Lock()
+	defer client.commitMu.Unlock()
+	if client.commitLog != nil {
+		client.commitLog.Close()
+		client.commitLog = nil
+	}
+	fileName, err := commitLogFile()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot clear the commit log:", err)
+	}
+}
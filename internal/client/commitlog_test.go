@@ -0,0 +1,50 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestContinueSkipsDeleted(t *testing.T) {
+	tests := []struct {
+		continueRun bool
+		want        []string
+	}{
+		{false, []string{"1", "2", "3"}},
+		{true, []string{"3"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("continue=%v", tt.continueRun), func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			// a run that was interrupted after deleting two threads
+			aborted := &Client{opts: &Options{}}
+			for _, id := range []string{"1", "2"} {
+				aborted.logCommit(Notification{Id: id})
+			}
+			aborted.commitLog.Close()
+
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `[{"id": "1", "unread": true}, {"id": "2", "unread": true}, {"id": "3", "unread": true}]`)
+			})
+			client, _ := fakeClient(t, &Options{Continue: tt.continueRun}, handler)
+			client.checkCommitLog()
+
+			var got []string
+			for _, notification := range fetchAll(t, client) {
+				got = append(got, notification.Id)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("fetched %v, want %v", got, tt.want)
+			}
+
+			client.clearCommitLog()
+			fileName, _ := commitLogFile()
+			if _, err := os.Stat(fileName); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("the commit log is still there after the run completed: %v", err)
+			}
+		})
+	}
+}
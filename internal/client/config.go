@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the optional config file at ~/.config/gh-flush/config.yml, see
+// configFile.
+type Config struct {
+	// Queries are named sets of key: value conditions, usable with --query.
+	Queries map[string]map[string]string `yaml:"queries"`
+	// Reasons maps notification reasons to labels, on top of reasonLabels.
+	Reasons map[string]string `yaml:"reasons"`
+	// Defaults are flag values used unless given on the command line or in
+	// the environment, keyed by flag name. Repeatable flags take lists.
+	Defaults map[string]interface{} `yaml:"defaults"`
+	// Rules decide with --rules, see configRule.
+	Rules []configRule `yaml:"rules"`
+}
+
+// loadConfig reads the config file, a missing file is an empty config.
+func loadConfig() (*Config, error) {
+	config := new(Config)
+	fileName, err := configFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", fileName, err)
+	}
+	return config, nil
+}
+
+// query turns a saved query into a delete rule.
+func (config *Config) query(name string) (deleteRule, error) {
+	conditions, ok := config.Queries[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown query %q, see --list-queries", name)
+	}
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rule := deleteRule{}
+	for _, key := range keys {
+		c, err := newCondition(key, conditions[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid query %q: %w", name, err)
+		}
+		rule = append(rule, c)
+	}
+	if len(rule) == 0 {
+		return nil, fmt.Errorf("query %q has no conditions", name)
+	}
+	return rule, nil
+}
+
+func (config *Config) printQueries() {
+	names := make([]string, 0, len(config.Queries))
+	for name := range config.Queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		fileName, _ := configFile()
+		fmt.Printf("no queries defined in %s\n", fileName)
+		return
+	}
+	for _, name := range names {
+		rule, err := config.query(name)
+		if err != nil {
+			fmt.Printf("%s\t(%v)\n", name, err)
+			continue
+		}
+		fmt.Printf("%s\t%s\n", name, rule)
+	}
+}
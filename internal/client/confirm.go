@@ -0,0 +1,68 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirmSampleSize is how many matches --interactive-confirm lists.
+const confirmSampleSize = 10
+
+// ConfirmingAll reports whether the UI lists a sample of the matches and
+// asks once before deleting them.
+func (client *Client) ConfirmingAll() bool {
+	return client.opts.InteractiveConfirm
+}
+
+// PromptOnStderr makes --interactive-confirm ask on stderr and read the
+// answer from stdin instead of in the UI, for piped output. There is no way
+// to undo without the UI, so --undo-window is ignored.
+func (client *Client) PromptOnStderr() {
+	client.promptOnStderr = true
+	if client.opts.UndoWindow > 0 {
+		if !client.opts.Quiet {
+			fmt.Fprintln(os.Stderr, "gh flush: ignoring --undo-window, deletions can only be undone in a terminal")
+		}
+		client.opts.UndoWindow = 0
+	}
+}
+
+// FormatConfirmSample lists the first few matches about to be deleted, so
+// that they can be spot-checked.
+func FormatConfirmSample(matches []NotificationResult) string {
+	var sb strings.Builder
+	for _, res := range matches[:min(confirmSampleSize, len(matches))] {
+		fmt.Fprintf(&sb, "  [%s] %s\n", res.Notification.Repository.FullName, res.Notification.Subject.Title)
+	}
+	if more := len(matches) - confirmSampleSize; more > 0 {
+		fmt.Fprintf(&sb, "  … and %d more\n", more)
+	}
+	return sb.String()
+}
+
+// confirmOnStderr asks whether to flush the held matches, anything but y
+// keeps all of them.
+func (client *Client) confirmOnStderr() {
+	matches := []NotificationResult{}
+	for _, status := range client.held {
+		if status.acted() && !status.Simulated {
+			matches = append(matches, status)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "gh flush: about to flush %d notifications:\n%sFlush them? [y/N] ", len(matches), FormatConfirmSample(matches))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if strings.EqualFold(strings.TrimSpace(answer), "y") {
+		return
+	}
+	for i := range client.held {
+		if status := &client.held[i]; status.acted() && !status.Simulated {
+			status.cancel()
+			status.Declined = true
+		}
+	}
+}
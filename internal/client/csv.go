@@ -0,0 +1,43 @@
+package client
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var csvHeader = []string{"updated_at", "repo", "title", "type", "reason", "tags", "deleted", "dry_run", "error"}
+
+// printCSV writes the results as CSV with a header row, for spreadsheets.
+func (client *Client) printCSV() {
+	w := csv.NewWriter(client.out)
+	w.Write(csvHeader)
+	result, ok := client.GetNotificationResult()
+	for ok {
+		errText := ""
+		if result.Err != nil {
+			errText = result.Err.Error()
+		}
+		w.Write([]string{
+			result.Notification.UpdatedAt.Format(time.RFC3339),
+			result.Notification.Repository.FullName,
+			result.Notification.Subject.Title,
+			result.Notification.Subject.Type,
+			result.Notification.Reason,
+			strings.Join(ResultTags(result), " "),
+			strconv.FormatBool(result.Deleted),
+			strconv.FormatBool(result.Deleted && (client.opts.DryRun || result.Simulated)),
+			errText,
+		})
+		w.Flush()
+		client.flushLine()
+		result, ok = client.GetNotificationResult()
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write results:", err)
+	}
+}
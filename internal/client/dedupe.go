@@ -0,0 +1,30 @@
+package client
+
+// dedupe drops notifications that were returned more than once, which
+// happens when the inbox changes while paging. The most recently updated copy
+// of each thread is kept, in the position of its first occurrence.
+func dedupe(notifications []Notification) ([]Notification, int) {
+	seen := make(map[string]int, len(notifications))
+	unique := make([]Notification, 0, len(notifications))
+	for _, n := range notifications {
+		// ids are only unique per host, thread URLs include it
+		if i, ok := seen[n.Url]; ok {
+			if n.UpdatedAt.After(unique[i].UpdatedAt) {
+				unique[i] = n
+			}
+			continue
+		}
+		seen[n.Url] = len(unique)
+		unique = append(unique, n)
+	}
+	return unique, len(notifications) - len(unique)
+}
+
+func (client *Client) Verbose() bool {
+	return client.opts.Verbose
+}
+
+// Duplicates is the number of notifications dropped as duplicates.
+func (client *Client) Duplicates() int {
+	return client.duplicates
+}
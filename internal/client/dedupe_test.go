@@ -0,0 +1,45 @@
+package client
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDedupe(t *testing.T) {
+	at := func(url string, hoursAgo int) Notification {
+		var n Notification
+		n.Url = url
+		n.Id = url + "@" + strconv.Itoa(hoursAgo)
+		n.UpdatedAt = testNow.Add(-time.Duration(hoursAgo) * time.Hour)
+		return n
+	}
+	tests := []struct {
+		name           string
+		notifications  []Notification
+		want           []string
+		wantDuplicates int
+	}{
+		{"empty", nil, nil, 0},
+		{"no duplicates", []Notification{at("a", 1), at("b", 2)}, []string{"a@1", "b@2"}, 0},
+		{"newer copy wins in place", []Notification{at("a", 3), at("b", 2), at("a", 1)}, []string{"a@1", "b@2"}, 1},
+		{"older copy is dropped", []Notification{at("a", 1), at("a", 3), at("a", 2)}, []string{"a@1"}, 2},
+		{"same id on another host", []Notification{at("github.com/1", 1), at("ghe.example.com/1", 1)}, []string{"github.com/1@1", "ghe.example.com/1@1"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unique, duplicates := dedupe(tt.notifications)
+			got := []string{}
+			for _, n := range unique {
+				got = append(got, n.Id)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kept %v, want %v", got, tt.want)
+			}
+			if duplicates != tt.wantDuplicates {
+				t.Errorf("dropped %d, want %d", duplicates, tt.wantDuplicates)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// flagEnv is the environment variable that sets a flag's default, e.g.
+// GH_FLUSH_SKIP_BOTS for --skip-bots.
+func flagEnv(name string) string {
+	return "GH_FLUSH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// applyDefaults sets the flags that weren't given on the command line from
+// their environment variables, and failing that from the defaults in the
+// config file.
+func applyDefaults(flags *flag.FlagSet, config *Config) error {
+	unknown := []string{}
+	for name := range config.Defaults {
+		if flags.Lookup(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		fileName, _ := configFile()
+		return fmt.Errorf("unknown flags in the defaults of %s: %s", fileName, strings.Join(unknown, ", "))
+	}
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		if value, ok := os.LookupEnv(flagEnv(f.Name)); ok {
+			if setErr := flags.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("invalid %s: %w", flagEnv(f.Name), setErr)
+			}
+			return
+		}
+		value, ok := config.Defaults[f.Name]
+		if !ok {
+			return
+		}
+		values := []interface{}{value}
+		if list, isList := value.([]interface{}); isList {
+			values = list
+		}
+		for _, v := range values {
+			if setErr := flags.Set(f.Name, fmt.Sprint(v)); setErr != nil {
+				err = fmt.Errorf("invalid default for --%s in the config file: %w", f.Name, setErr)
+				return
+			}
+		}
+	})
+	return err
+}
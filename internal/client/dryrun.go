@@ -0,0 +1,100 @@
+package client
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const DryRunMark = "[dry]"
+
+func (client *Client) DryRun() bool {
+	return client.opts.DryRun
+}
+
+// Sampling reports whether only a --sample of matches is really deleted.
+func (client *Client) Sampling() bool {
+	return client.opts.Sample > 0
+}
+
+// dryRunFlag is the flag that only ever does a dry run, if one is given.
+func (opts *Options) dryRunFlag() string {
+	switch {
+	case opts.CheckPermissions:
+		return "--check-permissions"
+	case opts.Plan:
+		return "--plan"
+	case opts.VerifyPlan != "":
+		return "--verify-plan"
+	case opts.Estimate > 0:
+		return "--estimate"
+	}
+	return ""
+}
+
+// DryRunBanner explains that nothing is (or was) deleted and how to re-run
+// the same command for real.
+func (client *Client) DryRunBanner(finished bool) string {
+	verb := "will be"
+	if finished {
+		verb = "was"
+	}
+	by := client.opts.dryRunBy
+	switch {
+	case client.opts.ReadOnly:
+		return "READ-ONLY: nothing " + verb + " deleted, " + ReadOnlyEnv + " disables all changes on this machine."
+	case strings.HasPrefix(by, "--"):
+		return "DRY RUN: nothing " + verb + " deleted, " + by + " never deletes. To flush for real, run the command without it."
+	case by != "":
+		// --dry-run is not on the command line to be left out
+		return "DRY RUN: nothing " + verb + " deleted, " + by + " turns on --dry-run. To flush for real, run: " + RealRunCommand() + " --dry-run=false"
+	}
+	return "DRY RUN: nothing " + verb + " deleted. To flush for real, run: " + RealRunCommand()
+}
+
+// RealRunCommand reconstructs the current invocation without --dry-run.
+func RealRunCommand() string {
+	parts := []string{"gh", "flush"}
+	shorthands := boolShorthands(flag.CommandLine)
+	for _, arg := range os.Args[1:] {
+		switch {
+		case arg == "--dry-run" || arg == "--dry-run=true" || arg == "-n":
+			continue
+		case isBoolShortFlagCluster(arg, shorthands):
+			arg = strings.ReplaceAll(arg, "n", "")
+			if arg == "-" {
+				continue
+			}
+		}
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+// boolShorthands are the shorthands of the boolean flags of a flag set.
+func boolShorthands(flags *flag.FlagSet) string {
+	var shorthands strings.Builder
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Shorthand != "" && f.Value.Type() == "bool" {
+			shorthands.WriteString(f.Shorthand)
+		}
+	})
+	return shorthands.String()
+}
+
+// isBoolShortFlagCluster matches combined boolean short flags like -bn.
+func isBoolShortFlagCluster(arg, shorthands string) bool {
+	if len(arg) < 2 || arg[0] != '-' || arg[1] == '-' {
+		return false
+	}
+	return strings.Trim(arg[1:], shorthands) == ""
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=./*,:@") == "" {
+		return arg
+	}
+	return strconv.Quote(arg)
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	flag "github.com/spf13/pflag"
+)
+
+func TestIsBoolShortFlagCluster(t *testing.T) {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.BoolP("skip-bots", "b", false, "")
+	flags.BoolP("dry-run", "n", false, "")
+	flags.BoolP("quiet", "q", false, "")
+	flags.IntP("workers", "w", 1, "")
+	flags.Bool("verbose", false, "")
+	shorthands := boolShorthands(flags)
+
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{"-n", true},
+		{"-bn", true},
+		{"-qbn", true},
+		{"-nw", false},
+		{"-w4", false},
+		{"-", false},
+		{"--dry-run", false},
+		{"--bn", false},
+		{"bn", false},
+		{"-x", false},
+	}
+	for _, tt := range tests {
+		if got := isBoolShortFlagCluster(tt.arg, shorthands); got != tt.want {
+			t.Errorf("isBoolShortFlagCluster(%q, %q) = %v, want %v", tt.arg, shorthands, got, tt.want)
+		}
+	}
+}
+
+func TestDryRunBanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		want     string
+		wantFlag bool
+	}{
+		{"--dry-run", Options{DryRun: true}, "To flush for real, run: gh flush", false},
+		{"--plan", Options{DryRun: true, Plan: true}, "--plan never deletes", false},
+		{"--estimate", Options{DryRun: true, Estimate: 10}, "--estimate never deletes", false},
+		{"environment", Options{DryRun: true, dryRunBy: "GH_FLUSH_DRY_RUN"}, "GH_FLUSH_DRY_RUN turns on --dry-run", true},
+		{"read-only", Options{DryRun: true, ReadOnly: true}, "READ-ONLY", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			if by := opts.dryRunFlag(); by != "" {
+				opts.dryRunBy = by
+			}
+			banner := (&Client{opts: &opts}).DryRunBanner(true)
+			if !strings.Contains(banner, tt.want) {
+				t.Errorf("DryRunBanner() = %q, want it to mention %q", banner, tt.want)
+			}
+			if got := strings.HasSuffix(banner, " --dry-run=false"); got != tt.wantFlag {
+				t.Errorf("DryRunBanner() = %q, turns off --dry-run: %v, want %v", banner, got, tt.wantFlag)
+			}
+		})
+	}
+}
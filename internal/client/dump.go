@@ -0,0 +1,31 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type notificationDump struct {
+	FetchedAt     time.Time      `json:"fetched_at"`
+	Truncated     string         `json:"truncated,omitempty"`
+	Notifications []Notification `json:"notifications"`
+}
+
+// dumpNotifications writes everything that was fetched, before anything gets
+// deleted.
+func (client *Client) dumpNotifications(fileName string) error {
+	data, err := json.MarshalIndent(notificationDump{
+		FetchedAt:     time.Now().UTC(),
+		Truncated:     client.truncated,
+		Notifications: client.notifications,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(fileName, data, 0o644); err != nil {
+		return fmt.Errorf("cannot dump notifications: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const maxErrorSamples = 5
+
+// APIErrorSummary aggregates the API errors of a run.
+type APIErrorSummary struct {
+	Count int
+	// ByStatus counts errors by HTTP status, 0 means no response at all.
+	ByStatus   map[int]int
+	ByEndpoint map[string]int
+	// Samples holds the first few error messages.
+	Samples []string
+	// Skipped counts notifications left alone because looking them up
+	// failed.
+	Skipped int
+}
+
+// recordAPIError counts a failed request and returns it as an *APIError.
+func (client *Client) recordAPIError(endpoint string, err error) error {
+	apiErr := newAPIError(endpoint, err)
+	status := apiErr.StatusCode
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	s := &client.apiErrors
+	if s.ByStatus == nil {
+		s.ByStatus = map[int]int{}
+		s.ByEndpoint = map[string]int{}
+	}
+	s.Count++
+	s.ByStatus[status]++
+	s.ByEndpoint[endpoint]++
+	if len(s.Samples) < maxErrorSamples {
+		sample := fmt.Sprintf("%s: %v", endpoint, err)
+		if client.anonymizer != nil {
+			// errors contain URLs with repository names
+			sample = fmt.Sprintf("%s: HTTP %d", endpoint, status)
+		}
+		s.Samples = append(s.Samples, sample)
+	}
+	return apiErr
+}
+
+func (client *Client) recordSkipped() {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.apiErrors.Skipped++
+}
+
+// APIErrors returns the API errors encountered so far.
+func (client *Client) APIErrors() APIErrorSummary {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	s := client.apiErrors
+	s.Samples = append([]string{}, s.Samples...)
+	return s
+}
+
+func (s APIErrorSummary) String() string {
+	if s.Count == 0 {
+		return "API errors: none\n"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "API errors: %d, %d notifications skipped\n", s.Count, s.Skipped)
+
+	statuses := make([]int, 0, len(s.ByStatus))
+	for status := range s.ByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for _, status := range statuses {
+		label := fmt.Sprintf("HTTP %d", status)
+		if status == 0 {
+			label = "no response"
+		}
+		fmt.Fprintf(&sb, "  %-12s %d\n", label, s.ByStatus[status])
+	}
+
+	endpoints := make([]string, 0, len(s.ByEndpoint))
+	for endpoint := range s.ByEndpoint {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		fmt.Fprintf(&sb, "  %-12s %d\n", endpoint, s.ByEndpoint[endpoint])
+	}
+
+	sb.WriteString("  e.g.\n")
+	for _, sample := range s.Samples {
+		fmt.Fprintf(&sb, "    %s\n", sample)
+	}
+	return sb.String()
+}
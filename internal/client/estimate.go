@@ -0,0 +1,88 @@
+package client
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// estimate extrapolates a dry run on a random sample of the notifications
+// to all of them, for --estimate.
+type estimate struct {
+	total   int
+	sampled int
+	matched int
+	// byTag counts the matching notifications of the sample by tag
+	byTag map[string]int
+}
+
+// sampleForEstimate keeps a random --estimate percent of the fetched
+// notifications, at least one.
+func (client *Client) sampleForEstimate() {
+	total := len(client.notifications)
+	n := min(max(int(math.Ceil(float64(total)*client.opts.Estimate/100)), 1), total)
+	rand.Shuffle(total, func(i, j int) {
+		client.notifications[i], client.notifications[j] = client.notifications[j], client.notifications[i]
+	})
+	client.notifications = client.notifications[:n]
+	client.estimate = &estimate{total: total, sampled: n, byTag: map[string]int{}}
+}
+
+func (client *Client) tallyEstimate(status NotificationResult) {
+	if client.estimate == nil || !status.Deleted {
+		return
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.estimate.matched++
+	for _, tag := range ResultTags(status) {
+		client.estimate.byTag[tag]++
+	}
+}
+
+// EstimateReport extrapolates the sample of an --estimate run, it is empty
+// otherwise.
+func (client *Client) EstimateReport() string {
+	if client.estimate == nil {
+		return ""
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	e := client.estimate
+	if e.sampled == 0 {
+		return "Nothing to estimate, no notifications were fetched"
+	}
+	scale := float64(e.total) / float64(e.sampled)
+	extrapolate := func(n int) string {
+		return humanize.Comma(int64(math.Round(float64(n) * scale)))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "~%s of %s likely to flush, based on a %g%% sample of %d", extrapolate(e.matched), humanize.Comma(int64(e.total)), client.opts.Estimate, e.sampled)
+	if e.sampled < e.total {
+		// 95% confidence interval of the proportion, with finite population
+		// correction
+		p := float64(e.matched) / float64(e.sampled)
+		fpc := math.Sqrt(float64(e.total-e.sampled) / float64(max(e.total-1, 1)))
+		margin := 1.96 * math.Sqrt(p*(1-p)/float64(e.sampled)) * fpc * float64(e.total)
+		fmt.Fprintf(&sb, " (±%s)", humanize.Comma(int64(math.Round(margin))))
+	}
+	tags := make([]string, 0, len(e.byTag))
+	for tag := range e.byTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if e.byTag[tags[i]] != e.byTag[tags[j]] {
+			return e.byTag[tags[i]] > e.byTag[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	for _, tag := range tags {
+		fmt.Fprintf(&sb, "\n  %-18s ~%s", tag, extrapolate(e.byTag[tag]))
+	}
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package client
+
+// Exit codes, see ExitCode.
+const (
+	ExitOK = 0
+	// ExitError is used for fatal errors, and when fetching stopped on an
+	// error after some notifications were already flushed.
+	ExitError = 1
+	// ExitNothingMatched is used with --strict-nothing when no notification
+	// matched the delete rules.
+	ExitNothingMatched = 3
+	// ExitPermissionDenied is used with --check-permissions when the token
+	// cannot act on the matching notifications.
+	ExitPermissionDenied = 4
+	// ExitAPIErrors is used when notifications could not be looked up or
+	// deleted, see FailureReport, and with --fail-on-any-error when any API
+	// request failed.
+	ExitAPIErrors = 5
+	// ExitPlanDrift is used with --verify-plan when more than --max-drift
+	// notifications changed since the plan was made.
+	ExitPlanDrift = 6
+)
+
+const NothingMatchedMessage = "No notifications matched your rules, nothing to flush 🎉"
+
+// NothingMatched reports whether no notification matched the rules, no
+// matter whether the matches were flushed in the end.
+func (client *Client) NothingMatched() bool {
+	return client.numMatched.Load() == 0
+}
+
+// ExitCode is what gh flush should exit with once the run is done.
+func (client *Client) ExitCode() int {
+	if client.fetchErr != nil {
+		return ExitError
+	}
+	if client.PermissionsFailed() {
+		return ExitPermissionDenied
+	}
+	if client.opts.VerifyPlan != "" && client.drift > client.opts.MaxDrift {
+		return ExitPlanDrift
+	}
+	if client.FailureReport() != "" || client.opts.FailOnAnyError && client.APIErrors().Count > 0 {
+		return ExitAPIErrors
+	}
+	if client.opts.StrictNothing && client.NothingMatched() {
+		return ExitNothingMatched
+	}
+	return ExitOK
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestExitCodeOnAPIErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		failDelete     bool
+		otherError     bool
+		failOnAnyError bool
+		want           int
+	}{
+		{"all deleted", false, false, false, ExitOK},
+		{"failed delete", true, false, false, ExitAPIErrors},
+		{"failed delete with --fail-on-any-error", true, false, true, ExitAPIErrors},
+		{"other request failed", false, true, false, ExitOK},
+		{"other request failed with --fail-on-any-error", false, true, true, ExitAPIErrors},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+				}
+				if tt.failDelete && r.URL.Path == "/notifications/threads/2" {
+					http.Error(w, `{"message": "Server Error"}`, http.StatusInternalServerError)
+					return
+				}
+				w.WriteHeader(http.StatusResetContent)
+			})
+			client, api := fakeClient(t, &Options{FailOnAnyError: tt.failOnAnyError}, handler)
+
+			for _, id := range []string{"1", "2", "3"} {
+				status := NotificationResult{Read: true}
+				status.Notification.Id = id
+				status.Notification.Url = api + "notifications/threads/" + id
+				client.statuses <- status
+			}
+			close(client.statuses)
+			if tt.otherError {
+				client.recordAPIError(endpointSubscription, errors.New("connection reset"))
+			}
+			client.wgDeleter.Add(1)
+			client.deleteNotifications()
+			client.finish()
+
+			if got := client.ExitCode(); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+			if got := client.FailureReport() != ""; got != tt.failDelete {
+				t.Errorf("FailureReport() reports a failure: %v, want %v", got, tt.failDelete)
+			}
+		})
+	}
+}
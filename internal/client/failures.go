@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type failedDeletion struct {
+	Id    string `json:"id"`
+	Url   string `json:"url"`
+	Repo  string `json:"repo"`
+	Title string `json:"title"`
+	Error string `json:"error"`
+}
+
+func failuresFile() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "failures.json"), nil
+}
+
+func (client *Client) recordFailure(result NotificationResult) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.failures = append(client.failures, failedDeletion{
+		Id:    result.Notification.Id,
+		Url:   result.Notification.Url,
+		Repo:  result.Notification.Repository.FullName,
+		Title: result.Notification.Subject.Title,
+		Error: result.Err.Error(),
+	})
+}
+
+// saveFailures writes failed deletions to the failures file for
+// --resume-failed, or removes the file once a run had no failures.
+func (client *Client) saveFailures() {
+	if client.opts.DryRun {
+		return
+	}
+	fileName, err := failuresFile()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot save failed deletions:", err)
+		return
+	}
+	if len(client.failures) == 0 {
+		if err := os.Remove(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintln(os.Stderr, "gh flush: cannot clear failed deletions:", err)
+		}
+		return
+	}
+
+	data, err := json.MarshalIndent(client.failures, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fileName), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(fileName, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot save failed deletions:", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "gh flush: %d deletions failed, retry them with --resume-failed\n", len(client.failures))
+}
+
+// loadFailures turns the failures file back into notification stubs.
+func loadFailures() ([]Notification, error) {
+	fileName, err := failuresFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Notification{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	failures := []failedDeletion{}
+	if err := json.Unmarshal(data, &failures); err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %w", fileName, err)
+	}
+	notifications := make([]Notification, 0, len(failures))
+	for _, f := range failures {
+		n := Notification{Id: f.Id, Url: f.Url}
+		n.Repository.FullName = f.Repo
+		n.Subject.Title = f.Title
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// fail keeps a notification that cannot be acted on and remembers why.
+func fail(status *NotificationResult, err error) {
+	status.Deleted = false
+	status.Err = err
+}
+
+// FailureReport lists the notifications that could not be looked up or
+// deleted, it is empty when nothing failed.
+func (client *Client) FailureReport() string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.failed) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d notifications failed:\n", len(client.failed))
+	for _, res := range client.failed {
+		fmt.Fprintf(&sb, "  %s [%s] %s: %v\n", res.Notification.Id, res.Notification.Repository.FullName, res.Notification.Subject.Title, res.Err)
+	}
+	return sb.String()
+}
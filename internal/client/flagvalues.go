@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// subjectTypes are the notification subject types GitHub sends.
+var subjectTypes = []string{"CheckSuite", "Commit", "Discussion", "Issue", "PullRequest", "Release", "RepositoryVulnerabilityAlert"}
+
+// flagValues lists the valid values of the flags that take one of a known
+// set, for shell completion through --list-flag-values.
+func flagValues(config *Config) map[string][]string {
+	reasons := make([]string, 0, len(reasonLabels))
+	for reason := range reasonLabels {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	deleteWhen := []string{}
+	for _, key := range ruleKeyNames() {
+		switch key {
+		case "reason":
+			for _, reason := range reasons {
+				deleteWhen = append(deleteWhen, "reason="+reason)
+			}
+		case "type":
+			for _, t := range subjectTypes {
+				deleteWhen = append(deleteWhen, "type="+t)
+			}
+		case "unread":
+			deleteWhen = append(deleteWhen, "unread=true", "unread=false")
+		default:
+			deleteWhen = append(deleteWhen, key+"=")
+		}
+	}
+
+	templates := make([]string, 0, len(namedTemplates))
+	for name := range namedTemplates {
+		templates = append(templates, name)
+	}
+	sort.Strings(templates)
+
+	queries := make([]string, 0, len(config.Queries))
+	for name := range config.Queries {
+		queries = append(queries, name)
+	}
+	sort.Strings(queries)
+
+	return map[string][]string{
+		"format":         {FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatMarkdown},
+		"progress-style": {ProgressBar, ProgressPercentage, ProgressSpinner, ProgressNone},
+		"hook-timing":    {HookEach, HookEnd},
+		"report-format":  {FormatJSON, FormatMarkdown},
+		"order-by":       {OrderUpdated, OrderRepoVolume},
+		"mode":           {ModeDelete, ModeDone, ModeRead},
+		"json-fields":    jsonFieldNames(),
+		"template":       templates,
+		"query":          queries,
+		"delete-when":    deleteWhen,
+	}
+}
+
+// printFlagValues prints the values of a flag one per line.
+func printFlagValues(config *Config, name string) error {
+	values, ok := flagValues(config)[name]
+	if !ok {
+		return fmt.Errorf("--%s has no fixed set of values", name)
+	}
+	for _, value := range values {
+		fmt.Fprintln(os.Stdout, value)
+	}
+	return nil
+}
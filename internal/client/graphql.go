@@ -0,0 +1,158 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// prBatchSize is how many pull requests one GraphQL query looks up.
+const prBatchSize = 50
+
+const endpointGraphQL = "POST graphql"
+
+var pullUrlRE = regexp.MustCompile(`repos/([^/]+)/([^/]+)/pulls/(\d+)$`)
+
+// prFields are the GraphQL fields that make up a PullRequest.
+const prFields = `state merged isDraft updatedAt
+      author { login __typename }
+      comments { totalCount }
+      reviewThreads(first: 100) { nodes { comments { totalCount } } }
+      labels(first: 50) { nodes { name } }
+      baseRepository { nameWithOwner }`
+
+type graphQLPullRequest struct {
+	State     string
+	Merged    bool
+	IsDraft   bool
+	UpdatedAt time.Time
+	Author    *struct {
+		Login    string
+		Typename string `json:"__typename"`
+	}
+	Comments      struct{ TotalCount int }
+	ReviewThreads struct {
+		Nodes []struct {
+			Comments struct{ TotalCount int }
+		}
+	}
+	Labels         struct{ Nodes []Label }
+	BaseRepository struct{ NameWithOwner string }
+}
+
+// pullRequest converts to what the REST API returns.
+func (gpr graphQLPullRequest) pullRequest() *PullRequest {
+	pr := &PullRequest{
+		State:          strings.ToLower(gpr.State),
+		Merged:         gpr.Merged,
+		Draft:          gpr.IsDraft,
+		UpdatedAt:      gpr.UpdatedAt,
+		Comments:       gpr.Comments.TotalCount,
+		ReviewComments: gpr.reviewComments(),
+		Labels:         gpr.Labels.Nodes,
+	}
+	if pr.State == "merged" {
+		pr.State = "closed"
+	}
+	if gpr.Author != nil {
+		pr.User.Login = gpr.Author.Login
+		pr.User.Type = gpr.Author.Typename
+		if pr.User.Type == "Bot" {
+			// REST logins of apps carry the suffix
+			pr.User.Login += "[bot]"
+		}
+	}
+	pr.Base.Repo.FullName = gpr.BaseRepository.NameWithOwner
+	return pr
+}
+
+// reviewComments counts the comments of the review threads like REST's
+// review_comments does. Only the first 100 threads are counted, which is
+// plenty for --active-threshold.
+func (gpr graphQLPullRequest) reviewComments() int {
+	n := 0
+	for _, thread := range gpr.ReviewThreads.Nodes {
+		n += thread.Comments.TotalCount
+	}
+	return n
+}
+
+// prefetchPullRequests looks up the pull requests of notifications in
+// batched GraphQL queries. tagNotifications falls back to REST for the ones
+// that couldn't be looked up, e.g. in renamed repositories.
+func (client *Client) prefetchPullRequests(notifications []Notification) {
+	urls := map[string][]string{}
+	seen := map[string]bool{}
+	for _, notification := range notifications {
+		subjectUrl := notification.Subject.Url
+		if notification.Subject.Type == "PullRequest" && pullUrlRE.MatchString(subjectUrl) && !seen[subjectUrl] {
+			seen[subjectUrl] = true
+			host := notification.Host()
+			urls[host] = append(urls[host], subjectUrl)
+		}
+	}
+	for host, hostUrls := range urls {
+		client.prefetchFrom(host, hostUrls)
+	}
+}
+
+// prefetchFrom looks up the pull requests of one host.
+func (client *Client) prefetchFrom(host string, urls []string) {
+	if host == "" {
+		host = client.hosts()[0]
+	}
+	gqlClient, err := client.graphQLClient(host)
+	if err != nil {
+		client.recordAPIError(endpointGraphQL, err)
+		return
+	}
+
+	for start := 0; start < len(urls); start += prBatchSize {
+		batch := urls[start:min(start+prBatchSize, len(urls))]
+		var query strings.Builder
+		query.WriteString("query {")
+		for i, subjectUrl := range batch {
+			m := pullUrlRE.FindStringSubmatch(subjectUrl)
+			fmt.Fprintf(&query, "\n  pr%d: repository(owner: %q, name: %q) {\n    pullRequest(number: %s) {\n      %s\n    }\n  }", i, m[1], m[2], m[3], prFields)
+		}
+		query.WriteString("\n}")
+
+		response := map[string]*struct{ PullRequest *graphQLPullRequest }{}
+		_, err := client.throttled(func() (http.Header, error) {
+			err := gqlClient.Do(query.String(), nil, &response)
+			client.logActivity(http.MethodPost, "graphql", statusOf(err, http.StatusOK))
+			return nil, err
+		})
+		var gqlErr *api.GraphQLError
+		if err != nil && !errors.As(err, &gqlErr) {
+			// nothing to salvage, REST will have to do
+			client.recordAPIError(endpointGraphQL, err)
+			continue
+		}
+		// pull requests missing from a partial response are looked up with
+		// REST, which records its own failures
+		client.mu.Lock()
+		if client.prefetched == nil {
+			client.prefetched = map[string]*PullRequest{}
+		}
+		for i, subjectUrl := range batch {
+			if repo := response[fmt.Sprintf("pr%d", i)]; repo != nil && repo.PullRequest != nil {
+				client.prefetched[subjectUrl] = repo.PullRequest.pullRequest()
+			}
+		}
+		client.mu.Unlock()
+	}
+}
+
+// prefetchedPullRequest returns a pull request looked up with GraphQL, or
+// nil if it has to be looked up with REST.
+func (client *Client) prefetchedPullRequest(subjectUrl string) *PullRequest {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	return client.prefetched[subjectUrl]
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// holdMatches reports whether matching notifications have to be held back
+// until all of them are known, because the decision depends on the others
+// or has to be confirmed.
+func (client *Client) holdMatches() bool {
+	return client.opts.Sample > 0 || client.opts.KeepRecentPerRepo > 0 || client.opts.Limit > 0 ||
+		client.opts.OrderBy != OrderUpdated || client.opts.InteractiveConfirm || client.Interactive()
+}
+
+// Interactive reports whether the matches have to be confirmed in the UI
+// before anything gets deleted, see Pending and ApplyPending.
+func (client *Client) Interactive() bool {
+	return client.opts.ConfirmPerRepo || client.opts.UndoWindow > 0 || client.opts.Review || client.opts.Select ||
+		(client.opts.InteractiveConfirm && !client.promptOnStderr)
+}
+
+// ConfirmingRepos reports whether the UI asks once per repository.
+func (client *Client) ConfirmingRepos() bool {
+	return client.opts.ConfirmPerRepo
+}
+
+// UndoWindow is how long the UI waits for an undo before deleting the
+// confirmed matches.
+func (client *Client) UndoWindow() time.Duration {
+	return client.opts.UndoWindow
+}
+
+func (client *Client) hold(status NotificationResult) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.held = append(client.held, status)
+}
+
+// applyHeld decides on and applies the held back matches once all
+// notifications have been tagged. In interactive mode the matches that are
+// still to be deleted are kept as pending instead.
+func (client *Client) applyHeld() {
+	if len(client.held) == 0 {
+		return
+	}
+	keepRecentPerRepo(client.held, client.opts.KeepRecentPerRepo)
+	if client.opts.Sample > 0 {
+		client.pickSample()
+	}
+	sort.SliceStable(client.held, func(i, j int) bool {
+		return client.held[i].Notification.UpdatedAt.After(client.held[j].Notification.UpdatedAt)
+	})
+	if client.opts.OrderBy == OrderRepoVolume {
+		orderByRepoVolume(client.held)
+	}
+	limit(client.held, client.opts.Limit)
+	if client.promptOnStderr && client.opts.InteractiveConfirm && !client.opts.DryRun {
+		client.confirmOnStderr()
+	}
+
+	for i := range client.held {
+		status := client.held[i]
+		if status.acted() && client.Interactive() {
+			client.pending = append(client.pending, status)
+			continue
+		}
+		ghApiClient, clientErr := client.restClient(status.Notification.Host())
+		if clientErr != nil {
+			fail(&status, clientErr)
+		}
+		client.apply(ghApiClient, &status)
+		client.send(status)
+	}
+}
+
+// Pending returns the matches waiting for confirmation once the results of
+// an interactive run are exhausted.
+func (client *Client) Pending() []NotificationResult {
+	return append([]NotificationResult{}, client.pending...)
+}
+
+// ApplyPending deletes the pending matches that approve accepts and keeps
+// the others. Their results can then be read with GetNotificationResult.
+func (client *Client) ApplyPending(approve func(NotificationResult) bool) {
+	pending := client.pending
+	client.pending = nil
+	client.results = make(chan NotificationResult)
+
+	go func() {
+		defer close(client.results)
+		for _, status := range pending {
+			if !approve(status) {
+				status.cancel()
+				status.Declined = true
+			}
+			ghApiClient, clientErr := client.restClient(status.Notification.Host())
+			if clientErr != nil {
+				fail(&status, clientErr)
+			}
+			client.apply(ghApiClient, &status)
+			client.send(status)
+		}
+		client.wrapUp()
+	}()
+}
+
+// keepRecentPerRepo protects the n most recently updated matches in each
+// repository.
+func keepRecentPerRepo(matches []NotificationResult, n int) {
+	if n <= 0 {
+		return
+	}
+	byRepo := map[string][]*NotificationResult{}
+	for i := range matches {
+		repo := matches[i].Notification.Repository.FullName
+		byRepo[repo] = append(byRepo[repo], &matches[i])
+	}
+	for _, repoMatches := range byRepo {
+		sort.Slice(repoMatches, func(i, j int) bool {
+			return repoMatches[i].Notification.UpdatedAt.After(repoMatches[j].Notification.UpdatedAt)
+		})
+		for _, status := range repoMatches[:min(n, len(repoMatches))] {
+			status.protect()
+			status.KeptRecent = true
+		}
+	}
+}
+
+// pickSample only really acts on --sample randomly chosen matches and
+// simulates the others.
+func (client *Client) pickSample() {
+	seed := client.opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	// workers append in arbitrary order, sort first so that a seed is reproducible
+	sort.Slice(client.held, func(i, j int) bool {
+		return client.held[i].Notification.Id < client.held[j].Notification.Id
+	})
+	rand.New(rand.NewSource(seed)).Shuffle(len(client.held), func(i, j int) {
+		client.held[i], client.held[j] = client.held[j], client.held[i]
+	})
+
+	picked := 0
+	for i := range client.held {
+		if !client.held[i].acted() {
+			continue
+		}
+		client.held[i].Simulated = picked >= client.opts.Sample
+		picked++
+	}
+}
+
+func formatSampleReport(results []NotificationResult) string {
+	flushed := []string{}
+	matched := 0
+	for _, res := range results {
+		if res.acted() {
+			matched++
+		}
+		if res.acted() && !res.Simulated {
+			flushed = append(flushed, fmt.Sprintf("  %s [%s] %s", res.Notification.Id, res.Notification.Repository.FullName, res.Notification.Subject.Title))
+		}
+	}
+	return fmt.Sprintf("Sample: really flushed %d of %d matching notifications:\n%s\n", len(flushed), matched, strings.Join(flushed, "\n"))
+}
+
+// Orders for --order-by.
+const (
+	OrderUpdated    = "updated"
+	OrderRepoVolume = "repo-volume"
+)
+
+// orderByRepoVolume puts the matches of the repositories with the most
+// matches first, so that a --limit makes the biggest dent. The order within
+// a repository is kept.
+func orderByRepoVolume(held []NotificationResult) {
+	volume := map[string]int{}
+	for _, status := range held {
+		if status.acted() {
+			volume[status.Notification.Repository.FullName]++
+		}
+	}
+	sort.SliceStable(held, func(i, j int) bool {
+		a, b := held[i].Notification.Repository.FullName, held[j].Notification.Repository.FullName
+		if volume[a] != volume[b] {
+			return volume[a] > volume[b]
+		}
+		return a < b
+	})
+}
+
+// limit keeps the matches beyond the first n that are really acted on.
+func limit(held []NotificationResult, n int) {
+	if n <= 0 {
+		return
+	}
+	acting := 0
+	for i := range held {
+		if !held[i].acted() || held[i].Simulated {
+			continue
+		}
+		if acting >= n {
+			held[i].cancel()
+			held[i].Limited = true
+			continue
+		}
+		acting++
+	}
+}
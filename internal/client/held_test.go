@@ -0,0 +1,166 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var testNow = time.Date(2024, 6, 12, 12, 0, 0, 0, time.UTC)
+
+// match is a result about to be deleted, updated hoursAgo.
+func match(id, repo string, hoursAgo int) NotificationResult {
+	res := NotificationResult{Deleted: true}
+	res.Notification.Id = id
+	res.Notification.Repository.FullName = repo
+	res.Notification.UpdatedAt = testNow.Add(-time.Duration(hoursAgo) * time.Hour)
+	return res
+}
+
+// ids lists the ids of the results that pick holds for.
+func ids(results []NotificationResult, pick func(NotificationResult) bool) string {
+	picked := []string{}
+	for _, res := range results {
+		if pick(res) {
+			picked = append(picked, res.Notification.Id)
+		}
+	}
+	return strings.Join(picked, ",")
+}
+
+func TestKeepRecentPerRepo(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		matches  []NotificationResult
+		wantKept string
+	}{
+		{"disabled", 0, []NotificationResult{match("1", "a/a", 1), match("2", "a/a", 2)}, ""},
+		{"newest per repo", 1, []NotificationResult{
+			match("1", "a/a", 5), match("2", "a/a", 1), match("3", "b/b", 3), match("4", "b/b", 9),
+		}, "2,3"},
+		{"fewer than n", 3, []NotificationResult{match("1", "a/a", 5), match("2", "b/b", 1)}, "1,2"},
+		{"two per repo", 2, []NotificationResult{
+			match("1", "a/a", 1), match("2", "a/a", 3), match("3", "a/a", 2), match("4", "a/a", 4),
+		}, "1,3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepRecentPerRepo(tt.matches, tt.n)
+			kept := ids(tt.matches, func(res NotificationResult) bool { return res.KeptRecent })
+			if kept != tt.wantKept {
+				t.Errorf("kept %q, want %q", kept, tt.wantKept)
+			}
+			for _, res := range tt.matches {
+				if res.KeptRecent && (res.Deleted || !res.Protected) {
+					t.Errorf("%s is kept but Deleted = %v, Protected = %v", res.Notification.Id, res.Deleted, res.Protected)
+				}
+			}
+		})
+	}
+}
+
+func TestLimit(t *testing.T) {
+	kept := match("2", "a/a", 1)
+	kept.Deleted = false
+	simulated := match("3", "a/a", 1)
+	simulated.Simulated = true
+	markRead := match("5", "a/a", 1)
+	markRead.Deleted, markRead.Action = false, ActionMarkRead
+	tests := []struct {
+		name        string
+		n           int
+		held        []NotificationResult
+		wantDeleted string
+		wantLimited string
+	}{
+		{"disabled", 0, []NotificationResult{match("1", "a/a", 1), match("2", "a/a", 1)}, "1,2", ""},
+		{"first n", 2, []NotificationResult{match("1", "a/a", 1), match("2", "a/a", 1), match("3", "b/b", 1)}, "1,2", "3"},
+		{"more than held", 5, []NotificationResult{match("1", "a/a", 1)}, "1", ""},
+		{"only counts deletions", 1, []NotificationResult{kept, match("1", "a/a", 1), match("4", "b/b", 1)}, "1", "4"},
+		{"skips simulated", 1, []NotificationResult{simulated, match("1", "a/a", 1), match("4", "b/b", 1)}, "3,1", "4"},
+		{"counts actions", 1, []NotificationResult{markRead, match("1", "a/a", 1)}, "", "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit(tt.held, tt.n)
+			for _, res := range tt.held {
+				if res.Limited && res.Action != "" {
+					t.Errorf("%s is limited but still to be %s", res.Notification.Id, res.Action)
+				}
+			}
+			if got := ids(tt.held, func(res NotificationResult) bool { return res.Deleted }); got != tt.wantDeleted {
+				t.Errorf("deleted %q, want %q", got, tt.wantDeleted)
+			}
+			if got := ids(tt.held, func(res NotificationResult) bool { return res.Limited }); got != tt.wantLimited {
+				t.Errorf("limited %q, want %q", got, tt.wantLimited)
+			}
+		})
+	}
+}
+
+func TestOrderByRepoVolume(t *testing.T) {
+	kept := match("6", "c/c", 1)
+	kept.Deleted = false
+	tests := []struct {
+		name string
+		held []NotificationResult
+		want string
+	}{
+		{"busiest first", []NotificationResult{
+			match("1", "a/a", 1), match("2", "b/b", 2), match("3", "b/b", 3), match("4", "a/a", 4), match("5", "b/b", 5),
+		}, "2,3,5,1,4"},
+		{"ties by name", []NotificationResult{match("1", "b/b", 1), match("2", "a/a", 2)}, "2,1"},
+		{"only matches count", []NotificationResult{
+			kept, kept, kept, match("7", "c/c", 2), match("1", "d/d", 1), match("2", "d/d", 2),
+		}, "1,2,6,6,6,7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderByRepoVolume(tt.held)
+			if got := ids(tt.held, func(NotificationResult) bool { return true }); got != tt.want {
+				t.Errorf("order %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModeReadIsHeld(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var mu sync.Mutex
+	requests := map[string]int{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusResetContent)
+	})
+	client, api := fakeClient(t, &Options{Mode: ModeRead, Limit: 1, OrderBy: OrderUpdated}, handler)
+
+	for _, id := range []string{"1", "2", "3"} {
+		status := NotificationResult{Read: true}
+		status.Notification.Id = id
+		status.Notification.Url = api + "notifications/threads/" + id
+		client.statuses <- status
+	}
+	close(client.statuses)
+	client.wgDeleter.Add(1)
+	client.deleteNotifications()
+	client.finish()
+
+	var results []NotificationResult
+	for res := range client.results {
+		results = append(results, res)
+	}
+	if got := ids(results, func(res NotificationResult) bool { return res.Action == ActionMarkRead }); len(got) != 1 {
+		t.Errorf("marked %q as read, want only one thread", got)
+	}
+	if got := ids(results, func(res NotificationResult) bool { return res.Limited }); len(got) != 3 {
+		t.Errorf("limited %q, want the other two threads", got)
+	}
+	if requests[http.MethodPatch] != 1 || requests[http.MethodDelete] != 0 {
+		t.Errorf("sent %v, want a single PATCH", requests)
+	}
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// runTotals are kept in the state directory to compare a run with the one
+// before.
+type runTotals struct {
+	FinishedAt time.Time `json:"finished_at"`
+	Fetched    int       `json:"fetched"`
+	Flushed    int       `json:"flushed"`
+}
+
+func lastRunFile() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last-run.json"), nil
+}
+
+// compareWithLastRun sums up this run against the previous one and saves
+// this run's totals for the next.
+func (client *Client) compareWithLastRun() {
+	if client.opts.DryRun {
+		return
+	}
+	fileName, err := lastRunFile()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot compare with the last run:", err)
+		return
+	}
+	var last *runTotals
+	if data, err := os.ReadFile(fileName); err == nil {
+		last = new(runTotals)
+		if err := json.Unmarshal(data, last); err != nil {
+			last = nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot compare with the last run:", err)
+	}
+
+	this := runTotals{
+		FinishedAt: time.Now().UTC(),
+		Fetched:    len(client.notifications),
+		Flushed:    int(client.numDeleted.Load()),
+	}
+	comparison := fmt.Sprintf("Flushed %d", this.Flushed)
+	if last != nil {
+		comparison += fmt.Sprintf(" (vs %d last run)", last.Flushed)
+	}
+	comparison += fmt.Sprintf(", backlog down from %d to %d", this.Fetched, this.Fetched-this.Flushed)
+	client.comparison = comparison
+
+	data, err := json.MarshalIndent(this, "", "  ")
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fileName), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(fileName, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot save the totals of this run:", err)
+	}
+}
+
+// RunComparison compares the finished run with the one before, it is empty
+// for dry runs.
+func (client *Client) RunComparison() string {
+	return client.comparison
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+const (
+	HookEach = "each"
+	HookEnd  = "end"
+)
+
+// runHook runs the --hook command through the shell with the given extra
+// environment. Failures are logged, never fatal.
+func (client *Client) runHook(env ...string) {
+	ctx, cancel := context.WithTimeout(context.Background(), client.opts.HookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", client.opts.Hook)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		fmt.Fprintf(os.Stderr, "gh flush: hook %q failed: %v\n", client.opts.Hook, err)
+	}
+}
+
+func (client *Client) runDeleteHook(notification Notification) {
+	if client.opts.Hook == "" || client.opts.HookTiming != HookEach {
+		return
+	}
+	client.runHook(
+		"GH_FLUSH_REPO="+notification.Repository.FullName,
+		"GH_FLUSH_TITLE="+notification.Subject.Title,
+		"GH_FLUSH_ID="+notification.Id,
+	)
+}
+
+func (client *Client) runEndHook() {
+	if client.opts.Hook == "" || client.opts.HookTiming != HookEnd {
+		return
+	}
+	client.runHook(
+		"GH_FLUSH_PROCESSED="+strconv.FormatInt(client.numProcessed.Load(), 10),
+		"GH_FLUSH_DELETED="+strconv.FormatInt(client.numDeleted.Load(), 10),
+	)
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
+)
+
+// hostLogin is the authenticated user on one host, looked up once.
+type hostLogin struct {
+	once  sync.Once
+	login string
+	err   error
+}
+
+// hosts are the GitHub hosts to flush: --hostname, or else GH_HOST or the
+// host gh is logged in to.
+func (client *Client) hosts() []string {
+	if len(client.opts.Hostnames) > 0 {
+		return client.opts.Hostnames
+	}
+	host, _ := auth.DefaultHost()
+	return []string{host}
+}
+
+// MultiHost reports whether the notifications of more than one host are
+// flushed.
+func (client *Client) MultiHost() bool {
+	return len(client.opts.Hostnames) > 1
+}
+
+// restClient returns the client for a host, the workers share them.
+func (client *Client) restClient(host string) (*api.RESTClient, error) {
+	if host == "" {
+		host = client.hosts()[0]
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if ghApiClient, ok := client.restClients[host]; ok {
+		return ghApiClient, nil
+	}
+	ghApiClient, err := api.NewRESTClient(api.ClientOptions{Host: host})
+	if err != nil {
+		return nil, err
+	}
+	if client.restClients == nil {
+		client.restClients = map[string]*api.RESTClient{}
+	}
+	client.restClients[host] = ghApiClient
+	return ghApiClient, nil
+}
+
+func (client *Client) graphQLClient(host string) (*api.GraphQLClient, error) {
+	return api.NewGraphQLClient(api.ClientOptions{Host: host})
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+type Label struct {
+	Name string
+}
+
+type Issue struct {
+	State    string
+	Labels   []Label
+	Comments int
+}
+
+// issue fetches the issue a notification is about, it is cached per
+// subject.
+func (client *Client) issue(ghApiClient *api.RESTClient, subjectUrl string) (*Issue, error) {
+	client.mu.Lock()
+	issue, ok := client.issues[subjectUrl]
+	client.mu.Unlock()
+	if ok {
+		return issue, nil
+	}
+
+	issue = new(Issue)
+	if err := client.get(ghApiClient, subjectUrl, issue); err != nil {
+		return nil, err
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.issues == nil {
+		client.issues = map[string]*Issue{}
+	}
+	client.issues[subjectUrl] = issue
+	return issue, nil
+}
+
+// labels returns the labels of the pull request or issue of a result.
+func (res *NotificationResult) labels() []Label {
+	switch {
+	case res.PR != nil:
+		return res.PR.Labels
+	case res.Issue != nil:
+		return res.Issue.Labels
+	}
+	return nil
+}
+
+// hasLabel reports whether any label of the subject is one of names,
+// ignoring case.
+func (res *NotificationResult) hasLabel(names []string) bool {
+	for _, label := range res.labels() {
+		for _, name := range names {
+			if strings.EqualFold(label.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
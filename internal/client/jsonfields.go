@@ -0,0 +1,65 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonFields maps the JSON names of JSONNotification's fields to their index.
+var jsonFields = func() map[string]int {
+	fields := map[string]int{}
+	t := reflect.TypeOf(JSONNotification{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = i
+	}
+	return fields
+}()
+
+func jsonFieldNames() []string {
+	t := reflect.TypeOf(JSONNotification{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+func validateJSONFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := jsonFields[field]; !ok {
+			return fmt.Errorf("unknown --json-fields field %q, valid fields are: %s", field, strings.Join(jsonFieldNames(), ", "))
+		}
+	}
+	return nil
+}
+
+// selectJSONFields keeps only the given fields of a notification.
+func selectJSONFields(n JSONNotification, fields []string) map[string]interface{} {
+	v := reflect.ValueOf(n)
+	selected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		selected[field] = v.Field(jsonFields[field]).Interface()
+	}
+	return selected
+}
+
+// jsonFieldReport is a JSONReport with only the --json-fields of each
+// notification.
+type jsonFieldReport struct {
+	JSONReport
+	Notifications []map[string]interface{} `json:"notifications"`
+}
+
+func newJSONFieldReport(report JSONReport, fields []string) jsonFieldReport {
+	selected := jsonFieldReport{
+		JSONReport:    report,
+		Notifications: make([]map[string]interface{}, 0, len(report.Notifications)),
+	}
+	for _, n := range report.Notifications {
+		selected.Notifications = append(selected.Notifications, selectJSONFields(n, fields))
+	}
+	return selected
+}
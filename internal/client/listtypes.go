@@ -0,0 +1,46 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ListingTypes reports whether --list-types was given.
+func (client *Client) ListingTypes() bool {
+	return client.opts.ListTypes
+}
+
+// PrintTypes prints the subject types and reasons of the fetched
+// notifications with their counts.
+func (client *Client) PrintTypes() {
+	types := map[string]int{}
+	reasons := map[string]int{}
+	for _, n := range client.notifications {
+		types[n.Subject.Type]++
+		reason := n.Reason
+		if label := client.ReasonLabel(reason); label != reason {
+			reason = label + " (" + reason + ")"
+		}
+		reasons[reason]++
+	}
+	fmt.Printf("%d notifications\n", len(client.notifications))
+	printCounts("Types", types)
+	printCounts("Reasons", reasons)
+}
+
+func printCounts(title string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	fmt.Printf("\n%s:\n", title)
+	for _, name := range names {
+		fmt.Printf("  %-36s %d\n", name, counts[name])
+	}
+}
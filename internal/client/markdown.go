@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"|", `\|`,
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func (client *Client) printMarkdown() {
+	client.writeMarkdown(client.out, client.collectResults())
+}
+
+func (client *Client) writeMarkdown(w io.Writer, results []NotificationResult) error {
+	flushed := 0
+	for _, res := range results {
+		if res.Deleted {
+			flushed++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## gh flush report\n\n")
+	if client.opts.DryRun {
+		sb.WriteString("**Dry run**, nothing was deleted.\n\n")
+	}
+	fmt.Fprintf(&sb, "Processed %d notifications on %s, flushed %d.\n\n", len(results), time.Now().Format(time.DateOnly), flushed)
+
+	repos := RepoSummary(results)
+	sb.WriteString("| Repository | Flushed | Kept |\n")
+	sb.WriteString("|------------|--------:|-----:|\n")
+	for _, repo := range repos {
+		fmt.Fprintf(&sb, "| %s | %d | %d |\n", escapeMarkdown(repo.Repo), repo.Flushed, repo.Kept)
+	}
+
+	for _, repo := range repos {
+		if repo.Flushed == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n<details>\n<summary>%s: %d flushed</summary>\n\n", escapeMarkdown(repo.Repo), repo.Flushed)
+		for _, res := range results {
+			if res.Deleted && res.Notification.Repository.FullName == repo.Repo {
+				tags := ""
+				if t := ResultTags(res); len(t) > 0 {
+					tags = " (" + strings.Join(t, ", ") + ")"
+				}
+				fmt.Fprintf(&sb, "- %s%s\n", escapeMarkdown(res.Notification.Subject.Title), tags)
+			}
+		}
+		sb.WriteString("\n</details>\n")
+	}
+	_, err := fmt.Fprint(w, sb.String())
+	return err
+}
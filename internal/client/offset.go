@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+func offsetFile() (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "offsets.json"), nil
+}
+
+// loadOffsets reads when each host was last fetched completely, for
+// --incremental.
+func loadOffsets() (map[string]time.Time, error) {
+	offsets := map[string]time.Time{}
+	fileName, err := offsetFile()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return offsets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &offsets); err != nil {
+		return nil, fmt.Errorf("invalid offset file %s: %w", fileName, err)
+	}
+	return offsets, nil
+}
+
+// fetchSince is when the last complete run fetched the notifications of a host,
+// or the zero time without --incremental or such a run. It notes when this
+// run started fetching it.
+func (client *Client) fetchSince(host string) time.Time {
+	if !client.opts.Incremental {
+		return time.Time{}
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.fetchStarted == nil {
+		client.fetchStarted = map[string]time.Time{}
+	}
+	client.fetchStarted[host] = time.Now()
+	return client.opts.offsets[host]
+}
+
+// saveOffsets remembers when this run started fetching, so that the next
+// --incremental run only fetches what was updated since. Runs that didn't
+// look at every notification, or left some alone that a later run could
+// flush without them being updated in between, leave the offsets alone.
+func (client *Client) saveOffsets() {
+	opts := client.opts
+	if !opts.Incremental || opts.DryRun || client.truncated != "" || opts.ResumeFailed || opts.ApplyPlan != "" ||
+		opts.subject != nil || len(opts.Repos) > 0 || len(opts.ExcludeRepos) > 0 || opts.Team != "" ||
+		len(opts.RepoTopics) > 0 || !opts.Before.IsZero() || !opts.Since.IsZero() || opts.Participating ||
+		len(opts.ProtectRepos) > 0 || len(opts.ProtectLabels) > 0 || opts.Limit > 0 || opts.Sample > 0 || opts.aging() {
+		return
+	}
+	client.mu.Lock()
+	if len(client.failed) > 0 {
+		client.mu.Unlock()
+		return
+	}
+	for host, started := range client.fetchStarted {
+		client.opts.offsets[host] = started.UTC()
+	}
+	data, err := json.MarshalIndent(client.opts.offsets, "", "  ")
+	client.mu.Unlock()
+
+	fileName, fileErr := offsetFile()
+	if err == nil {
+		err = fileErr
+	}
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(fileName), 0o755)
+	}
+	if err == nil {
+		err = os.WriteFile(fileName, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot save the offsets:", err)
+	}
+}
+
+// aging reports whether notifications are kept or flushed depending on
+// their age, so that one kept now may be flushed later as it is.
+func (opts *Options) aging() bool {
+	if opts.OlderThan > 0 || opts.NewerThan > 0 || opts.KeepMentionedWithin > 0 || opts.FlushCommitComments || opts.FlushStaleDrafts {
+		return true
+	}
+	for _, rule := range opts.actionRules {
+		if rule.olderThan > 0 || rule.newerThan > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatCSV      = "csv"
+	FormatMarkdown = "markdown"
+)
+
+func (client *Client) PrintResults() {
+	client.out = bufio.NewWriter(os.Stdout)
+	if client.opts.Progress && !client.opts.Quiet {
+		stop := make(chan struct{})
+		defer close(stop)
+		go client.reportProgress(stop)
+	}
+	switch {
+	case client.opts.template != nil:
+		client.printTemplate()
+	case client.opts.Format == FormatJSON:
+		client.printJSON()
+	case client.opts.Format == FormatNDJSON:
+		client.printNDJSON()
+	case client.opts.Format == FormatCSV:
+		client.printCSV()
+	case client.opts.Format == FormatMarkdown:
+		client.printMarkdown()
+	default:
+		client.printTable()
+	}
+	if err := client.out.Flush(); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write results:", err)
+	}
+	// with streaming, fetching is only over once the results are
+	if client.truncated != "" && (!client.opts.Quiet || client.fetchErr != nil) {
+		fmt.Fprintf(os.Stderr, "gh flush: not all notifications were fetched, %s\n", client.truncated)
+	}
+	if client.opts.Verbose && client.duplicates > 0 {
+		fmt.Fprintf(os.Stderr, "gh flush: dropped %d duplicate notifications\n", client.duplicates)
+	}
+	if client.NothingMatched() && !client.opts.Quiet {
+		fmt.Fprintln(os.Stderr, "gh flush:", NothingMatchedMessage)
+	}
+	if failures := client.FailureReport(); failures != "" {
+		fmt.Fprint(os.Stderr, "gh flush: ", failures)
+	}
+	if apiErrors := client.APIErrors(); client.opts.FailOnAnyError && apiErrors.Count > 0 {
+		fmt.Fprint(os.Stderr, apiErrors)
+	}
+	if report := client.EstimateReport(); report != "" {
+		fmt.Fprintln(os.Stderr, "gh flush:", report)
+	}
+	if report := client.PermissionReport(); report != "" {
+		fmt.Fprintln(os.Stderr, "gh flush:", report)
+	}
+}
+
+// flushLine writes out a result right away, so that whoever reads the
+// output through a pipe sees the results as they come.
+func (client *Client) flushLine() {
+	client.out.Flush()
+}
+
+// collectResults drains all results.
+func (client *Client) collectResults() []NotificationResult {
+	results := []NotificationResult{}
+	result, ok := client.GetNotificationResult()
+	for ok {
+		results = append(results, result)
+		result, ok = client.GetNotificationResult()
+	}
+	return results
+}
+
+func (client *Client) printTable() {
+	if client.opts.DryRun {
+		fmt.Fprintln(client.out, client.DryRunBanner(false))
+	}
+	fmt.Fprintln(client.out, "Time                \tReason [Repo] Title")
+
+	results := []NotificationResult{}
+	result, ok := client.GetNotificationResult()
+	for ok {
+		results = append(results, result)
+		reason := ""
+		if result.Deleted {
+			reason += Deleted
+		}
+		if result.Protected {
+			reason += Protected
+		}
+		if result.KeptRecent {
+			reason += Recent
+		}
+		if result.Active {
+			reason += Active
+		}
+		if result.Err != nil {
+			reason += Failed
+		}
+		if result.Read {
+			reason += Read
+		}
+		if result.MergedPR {
+			reason += MergedPR
+		} else if result.ClosedPR {
+			reason += ClosedPR
+		}
+		if result.ClosedIssue {
+			reason += ClosedIssue
+		}
+		if result.BotPR {
+			reason += BotPR
+		}
+		if result.StaleDraft {
+			reason += Stale
+		}
+		if result.NewActivity {
+			reason += NewActivity
+		}
+
+		if reason != "" {
+			reason += " "
+		}
+
+		if client.opts.DryRun || result.Simulated {
+			reason = DryRunMark + " " + reason
+		}
+
+		ts := result.Notification.UpdatedAt.Format(time.RFC3339)
+		repo := result.Notification.Repository.FullName
+		if client.MultiHost() {
+			repo = result.Notification.Host() + "/" + repo
+		}
+		if result.RenamedFrom != "" {
+			repo += " (was " + result.RenamedFrom + ")"
+		}
+		title := result.Notification.Subject.Title
+		if client.opts.Verbose {
+			title += " (" + formatTiming(result) + ")"
+		}
+		label := client.ReasonLabel(result.Notification.Reason)
+		fmt.Fprintf(client.out, "%s\t%s[%s] %s: %s\n", ts, reason, repo, label, title)
+		client.flushLine()
+		result, ok = client.GetNotificationResult()
+	}
+	if client.opts.Sample > 0 && !client.opts.DryRun {
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, formatSampleReport(results))
+	}
+	if client.opts.Summary {
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, formatAgeHistogram(AgeHistogram(results, time.Now())))
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, client.FormatReasonSummary(results))
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, client.APIErrors())
+		if comparison := client.RunComparison(); comparison != "" {
+			fmt.Fprintln(client.out, comparison)
+		}
+		if workers := client.SettledWorkers(); workers > 0 {
+			fmt.Fprintf(client.out, "Delete workers settled at %d\n", workers)
+		}
+	}
+	if client.opts.ShowUnflushedRepos {
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, FormatUnflushedRepos(results))
+	}
+	if slowest := FormatSlowest(results); slowest != "" {
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, slowest)
+	}
+	if client.MultiHost() {
+		fmt.Fprintln(client.out)
+		fmt.Fprint(client.out, FormatHostSummary(results))
+	}
+	if client.opts.DryRun {
+		fmt.Fprintln(client.out, client.DryRunBanner(true))
+	}
+}
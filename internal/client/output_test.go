@@ -0,0 +1,62 @@
+package client
+
+import (
+	"bufio"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// writeRecorder keeps what was written so far.
+type writeRecorder struct {
+	mu      sync.Mutex
+	written strings.Builder
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written.Write(p)
+}
+
+func (w *writeRecorder) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written.String()
+}
+
+func TestResultsAreWrittenOneByOne(t *testing.T) {
+	formats := []struct {
+		name  string
+		print func(*Client)
+	}{
+		{"table", (*Client).printTable},
+		{"ndjson", (*Client).printNDJSON},
+		{"csv", (*Client).printCSV},
+	}
+	for _, format := range formats {
+		t.Run(format.name, func(t *testing.T) {
+			recorder := &writeRecorder{}
+			client := &Client{opts: &Options{}, results: make(chan NotificationResult)}
+			client.out = bufio.NewWriter(recorder)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				format.print(client)
+			}()
+
+			for _, title := range []string{"first", "second", "third"} {
+				result := NotificationResult{}
+				result.Notification.Subject.Title = title
+				client.results <- result
+				// the next result is only read once the previous one is written
+				client.results <- NotificationResult{}
+				if !strings.Contains(recorder.String(), title) {
+					t.Errorf("%s was not written before the next result was read", title)
+				}
+			}
+			close(client.results)
+			<-done
+		})
+	}
+}
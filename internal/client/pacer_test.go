@@ -0,0 +1,41 @@
+package client
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPacerWait(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond float64
+		calls     int
+		wantMin   time.Duration
+	}{
+		{"no limit", 0, 10, 0},
+		{"negative rate", -1, 10, 0},
+		{"first call is free", 1, 1, 0},
+		{"spaced out", 100, 6, 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPacer(tt.perSecond)
+			start := time.Now()
+			// the workers share one pacer
+			var wg sync.WaitGroup
+			for i := 0; i < tt.calls; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					p.wait()
+				}()
+			}
+			wg.Wait()
+			elapsed := time.Since(start)
+			if elapsed < tt.wantMin || elapsed > tt.wantMin+time.Second {
+				t.Errorf("%d calls took %v, want about %v", tt.calls, elapsed, tt.wantMin)
+			}
+		})
+	}
+}
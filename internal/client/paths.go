@@ -0,0 +1,50 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// ConfigEnv overrides the location of the config file.
+const ConfigEnv = "GH_FLUSH_CONFIG"
+
+// configFile is $GH_FLUSH_CONFIG, or config.yml in the config directory.
+func configFile() (string, error) {
+	if fileName := os.Getenv(ConfigEnv); fileName != "" {
+		return fileName, nil
+	}
+	dir, err := baseDir("XDG_CONFIG_HOME", "APPDATA", ".config")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-flush", "config.yml"), nil
+}
+
+// stateDir is where gh-flush keeps files between runs.
+func stateDir() (string, error) {
+	dir, err := baseDir("XDG_STATE_HOME", "LOCALAPPDATA", filepath.Join(".local", "state"))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-flush"), nil
+}
+
+// baseDir resolves an XDG base directory from its variable, then on Windows
+// from the given one like %APPDATA%, and finally falls back to a directory
+// in the home directory.
+func baseDir(xdgEnv, windowsEnv, homeFallback string) (string, error) {
+	if dir := os.Getenv(xdgEnv); dir != "" {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv(windowsEnv); dir != "" {
+			return dir, nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, homeFallback), nil
+}
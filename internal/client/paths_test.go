@@ -0,0 +1,44 @@
+package client
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the home fallbacks differ on Windows")
+	}
+	home := t.TempDir()
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantConf  string
+		wantState string
+	}{
+		{"home fallback", nil,
+			filepath.Join(home, ".config", "gh-flush", "config.yml"),
+			filepath.Join(home, ".local", "state", "gh-flush")},
+		{"xdg", map[string]string{"XDG_CONFIG_HOME": "/xdg/config", "XDG_STATE_HOME": "/xdg/state"},
+			"/xdg/config/gh-flush/config.yml",
+			"/xdg/state/gh-flush"},
+		{"GH_FLUSH_CONFIG wins", map[string]string{ConfigEnv: "/etc/flush.yml", "XDG_CONFIG_HOME": "/xdg/config"},
+			"/etc/flush.yml",
+			filepath.Join(home, ".local", "state", "gh-flush")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			for _, name := range []string{ConfigEnv, "XDG_CONFIG_HOME", "XDG_STATE_HOME"} {
+				t.Setenv(name, tt.env[name])
+			}
+			if got, err := configFile(); err != nil || got != tt.wantConf {
+				t.Errorf("configFile() = %q, %v, want %q", got, err, tt.wantConf)
+			}
+			if got, err := stateDir(); err != nil || got != tt.wantState {
+				t.Errorf("stateDir() = %q, %v, want %q", got, err, tt.wantState)
+			}
+		})
+	}
+}
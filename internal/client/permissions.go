@@ -0,0 +1,109 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// permissionSample is how many matching threads --check-permissions looks at.
+const permissionSample = 3
+
+const endpointThreadCheck = "GET thread"
+
+type permissionCheck struct {
+	notification Notification
+	err          error
+}
+
+// CheckingPermissions reports whether this run only checks that the token
+// could delete the matching notifications.
+func (client *Client) CheckingPermissions() bool {
+	return client.opts.CheckPermissions
+}
+
+// checkPermission fetches the thread of a matching notification, which
+// needs the same access as deleting it, for the first few matches.
+func (client *Client) checkPermission(ghApiClient *api.RESTClient, status *NotificationResult) {
+	client.mu.Lock()
+	if client.permissionsChecked >= permissionSample {
+		client.mu.Unlock()
+		return
+	}
+	client.permissionsChecked++
+	client.mu.Unlock()
+
+	err := checkThread(ghApiClient, status.Notification.Url)
+	if err != nil {
+		err = client.recordAPIError(endpointThreadCheck, err)
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.permissionChecks = append(client.permissionChecks, permissionCheck{status.Notification, err})
+}
+
+func checkThread(ghApiClient *api.RESTClient, path string) error {
+	response, err := ghApiClient.Request(http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	// classic tokens list their scopes, either of these allows deleting
+	if scopes := response.Header.Get("X-OAuth-Scopes"); scopes != "" {
+		for _, scope := range strings.Split(scopes, ",") {
+			if s := strings.TrimSpace(scope); s == "notifications" || s == "repo" {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w, it needs the notifications or repo scope but has: %s", ErrInsufficientScope, scopes)
+	}
+	return nil
+}
+
+// PermissionsFailed reports whether --check-permissions found a thread the
+// token cannot act on.
+func (client *Client) PermissionsFailed() bool {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	for _, check := range client.permissionChecks {
+		if check.err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionReport summarizes --check-permissions, it is empty when the
+// flag isn't set.
+func (client *Client) PermissionReport() string {
+	if !client.opts.CheckPermissions {
+		return ""
+	}
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.permissionChecks) == 0 {
+		return "Permissions not checked, no notification matched your rules"
+	}
+	var sb strings.Builder
+	failed := 0
+	for _, check := range client.permissionChecks {
+		if check.err == nil {
+			continue
+		}
+		failed++
+		n := check.notification
+		reason := check.err.Error()
+		var httpErr *api.HTTPError
+		if errors.As(check.err, &httpErr) && httpErr.StatusCode == http.StatusForbidden {
+			reason = "403 Forbidden: " + httpErr.Message
+		}
+		fmt.Fprintf(&sb, "\n  [%s] %s: %s", n.Repository.FullName, n.Subject.Title, reason)
+	}
+	if failed == 0 {
+		return fmt.Sprintf("Permissions OK, checked %d matching notifications", len(client.permissionChecks))
+	}
+	return fmt.Sprintf("Permission check failed for %d of %d matching notifications:%s", failed, len(client.permissionChecks), sb.String())
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestCheckThread(t *testing.T) {
+	tests := []struct {
+		name      string
+		scopes    string
+		status    int
+		wantScope bool
+		wantErr   bool
+	}{
+		{"fine-grained token", "", http.StatusOK, false, false},
+		{"notifications scope", "read:org, notifications", http.StatusOK, false, false},
+		{"repo scope", "repo", http.StatusOK, false, false},
+		{"missing scope", "read:org, gist", http.StatusOK, true, true},
+		{"forbidden", "", http.StatusForbidden, true, true},
+		{"not found", "", http.StatusNotFound, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{}`))
+			})
+			client, api := fakeClient(t, &Options{}, handler)
+
+			err := checkThread(client.restClients[client.hosts()[0]], api+"notifications/threads/1")
+			if err != nil {
+				err = client.recordAPIError(endpointThreadCheck, err)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkThread() = %v, want an error: %v", err, tt.wantErr)
+			}
+			if got := errors.Is(err, ErrInsufficientScope); got != tt.wantScope {
+				t.Errorf("errors.Is(%v, ErrInsufficientScope) = %v, want %v", err, got, tt.wantScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PlanVersion is bumped whenever the plan format changes incompatibly.
+const PlanVersion = 1
+
+const PlanActionDelete = "delete"
+
+// Plan lists what a run would do, written by --plan and executed by
+// --apply-plan.
+type Plan struct {
+	Version     int          `json:"version"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Actions     []PlanAction `json:"actions"`
+}
+
+type PlanAction struct {
+	Id     string   `json:"id"`
+	Url    string   `json:"url"`
+	Repo   string   `json:"repo"`
+	Title  string   `json:"title"`
+	Action string   `json:"action"`
+	Rules  []string `json:"rules"`
+}
+
+// Planning reports whether --plan was given.
+func (client *Client) Planning() bool {
+	return client.opts.Plan
+}
+
+// PrintPlan writes the plan of a --plan run to stdout.
+func (client *Client) PrintPlan() {
+	plan := Plan{Version: PlanVersion, GeneratedAt: time.Now().UTC(), Actions: []PlanAction{}}
+	for _, res := range client.collectResults() {
+		if !res.Deleted {
+			continue
+		}
+		plan.Actions = append(plan.Actions, PlanAction{
+			Id:     res.Notification.Id,
+			Url:    res.Notification.Url,
+			Repo:   res.Notification.Repository.FullName,
+			Title:  res.Notification.Subject.Title,
+			Action: PlanActionDelete,
+			Rules:  ResultTags(res),
+		})
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(plan); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write plan:", err)
+	}
+}
+
+// loadPlan turns the actions of a plan file into notification stubs, they
+// are checked against GitHub before anything is applied.
+func loadPlan(fileName string) ([]Notification, error) {
+	plan, err := readPlan(fileName)
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]Notification, 0, len(plan.Actions))
+	for _, action := range plan.Actions {
+		if action.Action != PlanActionDelete {
+			return nil, fmt.Errorf("plan %s: unknown action %q for %s", fileName, action.Action, action.Id)
+		}
+		n := Notification{Id: action.Id, Url: action.Url}
+		n.Repository.FullName = action.Repo
+		n.Subject.Title = action.Title
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+func readPlan(fileName string) (*Plan, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	plan := new(Plan)
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, fmt.Errorf("cannot parse plan %s: %w", fileName, err)
+	}
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("plan %s has version %d, expected %d", fileName, plan.Version, PlanVersion)
+	}
+	return plan, nil
+}
+
+// VerifyingPlan reports whether --verify-plan was given.
+func (client *Client) VerifyingPlan() bool {
+	return client.opts.VerifyPlan != ""
+}
+
+// PrintPlanDrift compares what would be deleted now with the --verify-plan
+// plan: planned notifications that no longer match, and new matches.
+func (client *Client) PrintPlanDrift() error {
+	plan, err := readPlan(client.opts.VerifyPlan)
+	if err != nil {
+		return err
+	}
+	planned := map[string]PlanAction{}
+	for _, action := range plan.Actions {
+		planned[action.Id] = action
+	}
+
+	var added, removed []string
+	matched := map[string]bool{}
+	for _, res := range client.collectResults() {
+		n := res.Notification
+		if !res.Deleted {
+			if _, ok := planned[n.Id]; ok {
+				removed = append(removed, fmt.Sprintf("- [%s] %s (%s now)", n.Repository.FullName, n.Subject.Title, keptReason(res)))
+				matched[n.Id] = true
+			}
+			continue
+		}
+		matched[n.Id] = true
+		if _, ok := planned[n.Id]; !ok {
+			added = append(added, fmt.Sprintf("+ [%s] %s", n.Repository.FullName, n.Subject.Title))
+		}
+	}
+	for _, action := range plan.Actions {
+		if !matched[action.Id] {
+			removed = append(removed, fmt.Sprintf("- [%s] %s (read or gone)", action.Repo, action.Title))
+		}
+	}
+
+	client.drift = len(added) + len(removed)
+	fmt.Printf("Plan drift: %d planned notifications no longer match, %d new matches\n", len(removed), len(added))
+	for _, line := range append(removed, added...) {
+		fmt.Println("  " + line)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// Progress styles for the terminal UI, see --progress-style.
+const (
+	ProgressBar        = "bar"
+	ProgressPercentage = "percentage"
+	ProgressSpinner    = "spinner-only"
+	ProgressNone       = "none"
+)
+
+func (client *Client) ProgressStyle() string {
+	return client.opts.ProgressStyle
+}
+
+// progressInterval is how often --progress reports in piped mode.
+const progressInterval = 2 * time.Second
+
+// reportProgress writes how many notifications were processed to stderr
+// until stop is closed, for --progress in piped mode.
+func (client *Client) reportProgress(stop <-chan struct{}) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			total := fmt.Sprint(client.NotificationCount())
+			if !client.FetchDone() {
+				total += " fetched so far"
+			}
+			fmt.Fprintf(os.Stderr, "gh flush: processed %d/%s, flushed %d\n",
+				client.numProcessed.Load(), total, client.numDeleted.Load())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const (
+	// maxRateLimitRetries is how often a request is retried after GitHub
+	// pushed back.
+	maxRateLimitRetries = 3
+	// secondaryLimitBackoff is how long to pause after hitting a secondary
+	// rate limit that didn't say when to retry, as GitHub recommends.
+	secondaryLimitBackoff = time.Minute
+)
+
+// rateLimiter tracks the rate limit headers of all responses. Requests are
+// spread out once less than a tenth of the limit is left, and paused
+// altogether once it is used up or GitHub asks to back off.
+type rateLimiter struct {
+	mu          sync.Mutex
+	remaining   int
+	limit       int
+	reset       time.Time
+	pausedUntil time.Time
+}
+
+// wait blocks until the rate limit allows another request.
+func (r *rateLimiter) wait() {
+	r.mu.Lock()
+	delay := time.Until(r.pausedUntil)
+	if r.remaining > 0 && r.remaining*10 < r.limit {
+		delay = max(delay, time.Until(r.reset)/time.Duration(r.remaining))
+	}
+	r.mu.Unlock()
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// observe takes note of the rate limit of a response and reports whether
+// the request was rate limited and should be retried.
+func (r *rateLimiter) observe(header http.Header, err error) bool {
+	var httpErr *api.HTTPError
+	limited := errors.As(err, &httpErr) && isRateLimited(httpErr)
+	if httpErr != nil {
+		header = httpErr.Headers
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	remaining, err1 := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	limit, err2 := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	reset, err3 := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err1 == nil && err2 == nil && err3 == nil {
+		r.remaining, r.limit, r.reset = remaining, limit, time.Unix(reset, 0)
+		if remaining == 0 && r.reset.After(r.pausedUntil) {
+			r.pausedUntil = r.reset
+		}
+	}
+	if !limited {
+		return false
+	}
+	backoff := secondaryLimitBackoff
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		backoff = time.Duration(seconds) * time.Second
+	} else if r.remaining == 0 && !r.reset.IsZero() {
+		backoff = time.Until(r.reset)
+	}
+	if until := time.Now().Add(backoff); until.After(r.pausedUntil) {
+		r.pausedUntil = until
+	}
+	return true
+}
+
+// throttled makes a request once the rate limit allows it and retries it
+// when GitHub pushes back.
+func (client *Client) throttled(call func() (http.Header, error)) (http.Header, error) {
+	for attempt := 0; ; attempt++ {
+		client.rateLimits.wait()
+		header, err := call()
+		if !client.rateLimits.observe(header, err) || attempt >= maxRateLimitRetries {
+			return header, err
+		}
+	}
+}
+
+// isRateLimited reports whether GitHub refused a request because of the
+// primary or a secondary rate limit.
+func isRateLimited(httpErr *api.HTTPError) bool {
+	switch httpErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		return httpErr.Headers.Get("X-RateLimit-Remaining") == "0" || httpErr.Headers.Get("Retry-After") != "" ||
+			strings.Contains(strings.ToLower(httpErr.Message), "rate limit")
+	}
+	return false
+}
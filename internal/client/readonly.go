@@ -0,0 +1,46 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ReadOnlyEnv, when set to anything but 0, makes gh flush report only, no
+// matter which flags are passed. Meant for shared installations that must
+// never change anything.
+const ReadOnlyEnv = "GH_FLUSH_READONLY"
+
+var ErrReadOnly = errors.New("refusing to change anything, " + ReadOnlyEnv + " is set")
+
+// mutate sends a request that changes something on GitHub. All such requests
+// have to go through here, so that read-only mode cannot be bypassed. The
+// response headers are returned for rate limit bookkeeping, the body is
+// already closed. body may be nil.
+func (client *Client) mutate(ghApiClient *api.RESTClient, method, path string, body []byte) (http.Header, error) {
+	if client.opts.ReadOnly {
+		return nil, ErrReadOnly
+	}
+	return client.throttled(func() (http.Header, error) {
+		var reader io.Reader
+		if body != nil {
+			// retries need to send it again
+			reader = bytes.NewReader(body)
+		}
+		response, err := ghApiClient.Request(method, path, reader)
+		if err != nil {
+			client.logActivity(method, path, statusOf(err, 0))
+			var httpErr *api.HTTPError
+			if errors.As(err, &httpErr) {
+				return httpErr.Headers, err
+			}
+			return nil, err
+		}
+		client.logActivity(method, path, response.StatusCode)
+		response.Body.Close()
+		return response.Header, nil
+	})
+}
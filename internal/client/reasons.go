@@ -0,0 +1,33 @@
+package client
+
+// reasonLabels are friendlier names for GitHub's notification reasons, the
+// config file can override and extend them.
+var reasonLabels = map[string]string{
+	"approval_requested":       "Approval requested",
+	"assign":                   "Assigned",
+	"author":                   "Author",
+	"ci_activity":              "CI",
+	"comment":                  "Comment",
+	"invitation":               "Invitation",
+	"manual":                   "Subscribed",
+	"member_feature_requested": "Feature requested",
+	"mention":                  "Mentioned",
+	"review_requested":         "Review requested",
+	"security_advisory_credit": "Security advisory credit",
+	"security_alert":           "Security alert",
+	"state_change":             "State changed",
+	"subscribed":               "Watching",
+	"team_mention":             "Team mentioned",
+}
+
+// ReasonLabel turns a notification reason into a label for people, unknown
+// reasons are returned as they are.
+func (client *Client) ReasonLabel(reason string) string {
+	if label, ok := client.opts.reasonLabels[reason]; ok {
+		return label
+	}
+	if label, ok := reasonLabels[reason]; ok {
+		return label
+	}
+	return reason
+}
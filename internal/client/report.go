@@ -0,0 +1,292 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// JSONReportVersion is bumped on incompatible changes to JSONReport.
+const JSONReportVersion = 1
+
+// JSONReport is the document written by --format json. Its layout is a
+// stable contract for scripts; add fields, but don't rename or remove them
+// without bumping JSONReportVersion.
+type JSONReport struct {
+	Version       int                `json:"version"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	Truncated     string             `json:"truncated,omitempty"`
+	Options       JSONOptions        `json:"options"`
+	Totals        JSONTotals         `json:"totals"`
+	Notifications []JSONNotification `json:"notifications"`
+}
+
+type JSONOptions struct {
+	DryRun                bool     `json:"dry_run"`
+	Mode                  string   `json:"mode"`
+	Unsubscribe           bool     `json:"unsubscribe,omitempty"`
+	SkipPRsFromBots       bool     `json:"skip_bots"`
+	SkipClosedPRs         bool     `json:"skip_closed"`
+	SkipUnmergedClosed    bool     `json:"skip_unmerged_closed"`
+	SkipClosedIssues      bool     `json:"skip_closed_issues"`
+	SkipReadNotifications bool     `json:"skip_read"`
+	FlushOwnMerged        bool     `json:"flush_own_merged,omitempty"`
+	FlushStaleDrafts      string   `json:"flush_stale_drafts,omitempty"`
+	FlushStateChanges     bool     `json:"flush_state_changes,omitempty"`
+	Repos                 []string `json:"repos,omitempty"`
+	ExcludeRepos          []string `json:"exclude_repos,omitempty"`
+	ProtectRepos          []string `json:"protect_repos,omitempty"`
+	ProtectLabels         []string `json:"protect_labels,omitempty"`
+	DeleteWhen            []string `json:"delete_when,omitempty"`
+	Queries               []string `json:"queries,omitempty"`
+	KeepRecentPerRepo     int      `json:"keep_recent_per_repo,omitempty"`
+	Sample                int      `json:"sample,omitempty"`
+	ActiveThreshold       int      `json:"active_threshold,omitempty"`
+	KeepMentionedWithin   string   `json:"keep_mentioned_within,omitempty"`
+	OlderThan             string   `json:"older_than,omitempty"`
+	NewerThan             string   `json:"newer_than,omitempty"`
+}
+
+type JSONTotals struct {
+	Processed int `json:"processed"`
+	Deleted   int `json:"deleted"`
+	Kept      int `json:"kept"`
+	Failed    int `json:"failed"`
+}
+
+type JSONNotification struct {
+	Id          string    `json:"id"`
+	Host        string    `json:"host,omitempty"`
+	Repo        string    `json:"repo"`
+	RenamedFrom string    `json:"renamed_from,omitempty"`
+	Title       string    `json:"title"`
+	Type        string    `json:"type"`
+	Reason      string    `json:"reason"`
+	Url         string    `json:"url"`
+	Unread      bool      `json:"unread"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Author      string    `json:"author,omitempty"`
+	State       string    `json:"state,omitempty"`
+	Deleted     bool      `json:"deleted"`
+	DryRun      bool      `json:"dry_run"`
+	Tags        []string  `json:"tags"`
+	FetchMs     int64     `json:"fetch_ms,omitempty"`
+	DeleteMs    int64     `json:"delete_ms,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// ResultTags lists the rules and protections that applied to a result.
+func ResultTags(res NotificationResult) []string {
+	tags := []string{}
+	if res.BotPR {
+		tags = append(tags, "bot")
+	}
+	if res.ClosedPR {
+		tags = append(tags, "closed")
+	}
+	if res.ClosedIssue {
+		tags = append(tags, "closed-issue")
+	}
+	if res.MergedPR {
+		tags = append(tags, "merged")
+	}
+	if res.OwnPR {
+		tags = append(tags, "mine")
+	}
+	if res.StaleDraft {
+		tags = append(tags, "stale-draft")
+	}
+	if res.ObsoleteStateChange {
+		tags = append(tags, "state-change")
+	}
+	if res.Old {
+		tags = append(tags, "old")
+	}
+	if res.Commit {
+		tags = append(tags, "commit")
+	}
+	if res.Read {
+		tags = append(tags, "read")
+	}
+	if res.NewActivity {
+		tags = append(tags, "new-activity")
+	}
+	if res.ReviewState == ReviewChangesRequested {
+		tags = append(tags, "changes-requested")
+	}
+	if res.LastCommentMine {
+		tags = append(tags, "last-comment-mine")
+	}
+	if res.Subscription != "" {
+		tags = append(tags, res.Subscription)
+	}
+	if res.Protected {
+		tags = append(tags, "protected")
+	}
+	if res.KeptRecent {
+		tags = append(tags, "recent")
+	}
+	if res.Active {
+		tags = append(tags, "active")
+	}
+	if res.Labeled {
+		tags = append(tags, "labeled")
+	}
+	if res.RecentMention {
+		tags = append(tags, "recent-mention")
+	}
+	if res.Fresh {
+		tags = append(tags, "fresh")
+	}
+	if res.Action != "" {
+		tags = append(tags, res.Action)
+	}
+	if res.Unsubscribed {
+		tags = append(tags, "unsubscribed")
+	}
+	if res.Limited {
+		tags = append(tags, "over-limit")
+	}
+	if res.Excluded {
+		tags = append(tags, "excluded")
+	}
+	if res.Declined {
+		tags = append(tags, "declined")
+	}
+	return tags
+}
+
+// staleDraftOption reports --flush-stale-drafts by its age, if enabled.
+func staleDraftOption(opts *Options) string {
+	if !opts.FlushStaleDrafts {
+		return ""
+	}
+	age := ageValue(opts.StaleDraftAge)
+	return age.String()
+}
+
+// ageOption renders an optional age flag, empty when unset.
+func ageOption(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	age := ageValue(d)
+	return age.String()
+}
+
+func (client *Client) newJSONReport(results []NotificationResult) JSONReport {
+	opts := client.opts
+	report := JSONReport{
+		Version:     JSONReportVersion,
+		GeneratedAt: time.Now().UTC(),
+		Truncated:   client.truncated,
+		Options: JSONOptions{
+			DryRun:                opts.DryRun,
+			Mode:                  opts.Mode,
+			Unsubscribe:           opts.Unsubscribe,
+			SkipPRsFromBots:       opts.SkipPRsFromBots,
+			SkipClosedPRs:         opts.SkipClosedPRs,
+			SkipUnmergedClosed:    opts.SkipUnmergedClosed,
+			SkipClosedIssues:      opts.SkipClosedIssues,
+			SkipReadNotifications: opts.SkipReadNotifications,
+			FlushOwnMerged:        opts.FlushOwnMerged,
+			FlushStaleDrafts:      staleDraftOption(opts),
+			FlushStateChanges:     opts.FlushStateChanges,
+			Repos:                 opts.Repos,
+			ExcludeRepos:          opts.ExcludeRepos,
+			ProtectRepos:          opts.ProtectRepos,
+			ProtectLabels:         opts.ProtectLabels,
+			DeleteWhen:            opts.DeleteWhen,
+			Queries:               opts.Queries,
+			KeepRecentPerRepo:     opts.KeepRecentPerRepo,
+			Sample:                opts.Sample,
+			ActiveThreshold:       opts.ActiveThreshold,
+			KeepMentionedWithin:   ageOption(opts.KeepMentionedWithin),
+			OlderThan:             ageOption(opts.OlderThan),
+			NewerThan:             ageOption(opts.NewerThan),
+		},
+		Notifications: make([]JSONNotification, 0, len(results)),
+	}
+	for _, res := range results {
+		n := client.newJSONNotification(res)
+		report.Notifications = append(report.Notifications, n)
+
+		report.Totals.Processed++
+		switch {
+		case res.Err != nil:
+			report.Totals.Failed++
+		case res.Deleted:
+			report.Totals.Deleted++
+		default:
+			report.Totals.Kept++
+		}
+	}
+	return report
+}
+
+func (client *Client) newJSONNotification(res NotificationResult) JSONNotification {
+	n := JSONNotification{
+		Id:          res.Notification.Id,
+		Host:        res.Notification.Host(),
+		Repo:        res.Notification.Repository.FullName,
+		RenamedFrom: res.RenamedFrom,
+		Title:       res.Notification.Subject.Title,
+		Type:        res.Notification.Subject.Type,
+		Reason:      res.Notification.Reason,
+		Url:         res.Notification.Url,
+		Unread:      res.Notification.Unread,
+		UpdatedAt:   res.Notification.UpdatedAt,
+		Deleted:     res.Deleted,
+		DryRun:      res.Deleted && (client.opts.DryRun || res.Simulated),
+		Tags:        ResultTags(res),
+		FetchMs:     res.FetchTime.Milliseconds(),
+		DeleteMs:    res.DeleteTime.Milliseconds(),
+	}
+	if res.PR != nil {
+		n.Author = res.PR.User.Login
+		n.State = res.PR.State
+	} else if res.Issue != nil {
+		n.State = res.Issue.State
+	}
+	if res.Err != nil {
+		n.Error = res.Err.Error()
+	}
+	return n
+}
+
+func (client *Client) printJSON() {
+	if err := client.writeJSON(client.out, client.collectResults()); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write results:", err)
+	}
+}
+
+// printNDJSON writes each notification as a JSON object on its own line,
+// as soon as it is known.
+func (client *Client) printNDJSON() {
+	encoder := json.NewEncoder(client.out)
+	result, ok := client.GetNotificationResult()
+	for ok {
+		var n interface{} = client.newJSONNotification(result)
+		if len(client.opts.JSONFields) > 0 {
+			n = selectJSONFields(n.(JSONNotification), client.opts.JSONFields)
+		}
+		if err := encoder.Encode(n); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush: cannot write results:", err)
+			return
+		}
+		client.flushLine()
+		result, ok = client.GetNotificationResult()
+	}
+}
+
+func (client *Client) writeJSON(w io.Writer, results []NotificationResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	var report interface{} = client.newJSONReport(results)
+	if len(client.opts.JSONFields) > 0 {
+		report = newJSONFieldReport(report.(JSONReport), client.opts.JSONFields)
+	}
+	return encoder.Encode(report)
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// matchRepo reports whether fullName (owner/repo) matches any of the glob
+// patterns. Matching is case-insensitive, like GitHub repository names.
+func matchRepo(patterns []string, fullName string) bool {
+	name := strings.ToLower(fullName)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func validateRepoPatterns(flagName string, patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --%s pattern %q: %w", flagName, pattern, err)
+		}
+	}
+	return nil
+}
+
+// readPatternFile reads newline-delimited repo patterns, ignoring blank lines
+// and anything after a '#'.
+func readPatternFile(fileName string) ([]string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read repo pattern file: %w", err)
+	}
+	defer f.Close()
+
+	patterns := []string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read repo pattern file %s: %w", fileName, err)
+	}
+	return patterns, nil
+}
+
+// notificationsEndpoint uses the repository notifications endpoint when a
+// single repository is selected, so that we don't page through all of the
+// user's notifications only to filter them. Anything else is filtered client
+// side.
+func (opts *Options) notificationsEndpoint() string {
+	if len(opts.Repos) == 1 && !strings.ContainsAny(opts.Repos[0], `*?[\`) {
+		if owner, repo, ok := strings.Cut(opts.Repos[0], "/"); ok && owner != "" && repo != "" {
+			return "repos/" + owner + "/" + repo + "/notifications"
+		}
+	}
+	return "notifications"
+}
+
+// includeRepo applies --repo and --exclude-repo to a repository name.
+func (opts *Options) includeRepo(fullName string) bool {
+	if len(opts.Repos) > 0 && !matchRepo(opts.Repos, fullName) {
+		return false
+	}
+	return !matchRepo(opts.ExcludeRepos, fullName)
+}
+
+// maybeRenamed reports whether a pull request notification that --repo
+// leaves out could come from a repository since renamed into it. Only its
+// pull request tells the current name, so the check waits until then. That
+// costs a request per pull request outside --repo, it is only done with
+// --rest, which looks up every pull request on its own anyway.
+func (opts *Options) maybeRenamed(notification Notification) bool {
+	name := notification.Repository.FullName
+	return opts.RESTLookups && len(opts.Repos) > 0 && notification.Subject.Type == "PullRequest" &&
+		!matchRepo(opts.Repos, name) && !matchRepo(opts.ExcludeRepos, name)
+}
+
+// filterRepos drops the notifications that --repo and --exclude-repo leave
+// out.
+func (opts *Options) filterRepos(notifications []Notification) []Notification {
+	included := make([]Notification, 0, len(notifications))
+	for _, notification := range notifications {
+		if opts.includeRepo(notification.Repository.FullName) {
+			included = append(included, notification)
+		}
+	}
+	return included
+}
+
+// renamed switches a result over to the current name of a renamed or
+// transferred repository. GitHub answers requests for the old name with a
+// 301 that the HTTP client follows, so the fetched pull request already
+// carries the new name.
+func renamed(result *NotificationResult) {
+	current := result.PR.Base.Repo.FullName
+	if current == "" || strings.EqualFold(current, result.Notification.Repository.FullName) {
+		return
+	}
+	result.RenamedFrom = result.Notification.Repository.FullName
+	result.Notification.Repository.FullName = current
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestRenamedRepoMatchesRepo(t *testing.T) {
+	var api string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[
+			{"id": "1", "url": "%[1]snotifications/threads/1", "unread": true, "repository": {"full_name": "oldorg/app"},
+			 "subject": {"type": "PullRequest", "url": "%[1]srepos/oldorg/app/pulls/1"}},
+			{"id": "2", "url": "%[1]snotifications/threads/2", "unread": true, "repository": {"full_name": "other/lib"},
+			 "subject": {"type": "PullRequest", "url": "%[1]srepos/other/lib/pulls/2"}},
+			{"id": "3", "url": "%[1]snotifications/threads/3", "unread": true, "repository": {"full_name": "other/lib"},
+			 "subject": {"type": "Issue", "url": "%[1]srepos/other/lib/issues/3"}}
+		]`, api)
+	})
+	mux.HandleFunc("/repos/oldorg/app/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/api/v3/repos/neworg/app/pulls/1", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/repos/neworg/app/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state": "open", "base": {"repo": {"full_name": "neworg/app"}}}`)
+	})
+	mux.HandleFunc("/repos/other/lib/pulls/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"state": "open", "base": {"repo": {"full_name": "other/lib"}}}`)
+	})
+	client, api := fakeClient(t, &Options{Repos: []string{"neworg/*"}, RESTLookups: true}, mux)
+
+	fetched := fetchAll(t, client)
+	if len(fetched) != 2 {
+		t.Fatalf("fetched %d notifications, want the 2 pull requests", len(fetched))
+	}
+	client.numFetched.Store(int64(len(fetched)))
+	for _, notification := range fetched {
+		client.input <- notification
+	}
+	close(client.input)
+	client.wgFetcher.Add(1)
+	client.tagNotifications()
+	close(client.statuses)
+
+	var results []NotificationResult
+	for result := range client.statuses {
+		results = append(results, result)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want only the renamed repository", len(results))
+	}
+	if got := results[0].Notification.Repository.FullName; got != "neworg/app" {
+		t.Errorf("repository = %q, want neworg/app", got)
+	}
+	if got := results[0].RenamedFrom; got != "oldorg/app" {
+		t.Errorf("RenamedFrom = %q, want oldorg/app", got)
+	}
+	if got := client.NotificationCount(); got != 1 {
+		t.Errorf("NotificationCount() = %d, want 1", got)
+	}
+}
+
+func TestNotificationsEndpoint(t *testing.T) {
+	tests := []struct {
+		repos []string
+		want  string
+	}{
+		{nil, "notifications"},
+		{[]string{"cli/cli"}, "repos/cli/cli/notifications"},
+		{[]string{"cli/*"}, "notifications"},
+		{[]string{"cli/cl?"}, "notifications"},
+		{[]string{"cli/[a-c]li"}, "notifications"},
+		{[]string{"cli/cli", "cli/go-gh"}, "notifications"},
+		{[]string{"cli"}, "notifications"},
+		{[]string{"/cli"}, "notifications"},
+		{[]string{"cli/"}, "notifications"},
+	}
+	for _, tt := range tests {
+		opts := &Options{Repos: tt.repos}
+		if got := opts.notificationsEndpoint(); got != tt.want {
+			t.Errorf("notificationsEndpoint() with --repo %v = %q, want %q", tt.repos, got, tt.want)
+		}
+	}
+}
+
+func TestMaybeRenamedNeedsREST(t *testing.T) {
+	pr := Notification{}
+	pr.Repository.FullName = "oldorg/app"
+	pr.Subject.Type = "PullRequest"
+	issue := pr
+	issue.Subject.Type = "Issue"
+	tests := []struct {
+		name         string
+		opts         Options
+		notification Notification
+		want         bool
+	}{
+		{"GraphQL", Options{Repos: []string{"neworg/*"}}, pr, false},
+		{"REST", Options{Repos: []string{"neworg/*"}, RESTLookups: true}, pr, true},
+		{"issue", Options{Repos: []string{"neworg/*"}, RESTLookups: true}, issue, false},
+		{"no --repo", Options{RESTLookups: true}, pr, false},
+		{"excluded", Options{Repos: []string{"neworg/*"}, ExcludeRepos: []string{"oldorg/*"}, RESTLookups: true}, pr, false},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.maybeRenamed(tt.notification); got != tt.want {
+			t.Errorf("%s: maybeRenamed() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
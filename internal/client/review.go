@@ -0,0 +1,41 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const endpointMarkRead = "PATCH thread"
+
+// Reviewing reports whether the UI presents the matches one at a time to
+// be deleted or kept, for --interactive.
+func (client *Client) Reviewing() bool {
+	return client.opts.Review
+}
+
+// Selecting reports whether the UI lets the matches be checked and
+// unchecked before flushing them, for --select.
+func (client *Client) Selecting() bool {
+	return client.opts.Select
+}
+
+// MarkRead marks the thread of a notification as read, nothing happens in
+// a dry run.
+func (client *Client) MarkRead(res NotificationResult) error {
+	if client.opts.DryRun {
+		return nil
+	}
+	ghApiClient, err := client.restClient(res.Notification.Host())
+	if err != nil {
+		return err
+	}
+	return client.markRead(ghApiClient, res.Notification)
+}
+
+func (client *Client) markRead(ghApiClient *api.RESTClient, notification Notification) error {
+	if _, err := client.mutate(ghApiClient, http.MethodPatch, notification.Url, nil); err != nil {
+		return client.recordAPIError(endpointMarkRead, err)
+	}
+	return nil
+}
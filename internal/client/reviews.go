@@ -0,0 +1,61 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const (
+	ReviewChangesRequested = "changes_requested"
+	ReviewApproved         = "approved"
+)
+
+type review struct {
+	State string
+	User  struct {
+		Login string
+	}
+}
+
+// reviewState sums up the reviews of a pull request: changes_requested if
+// any reviewer's latest review requests changes, approved if there are
+// approvals and no requested changes. It is cached per pull request.
+func (client *Client) reviewState(ghApiClient *api.RESTClient, prUrl string) (string, error) {
+	client.mu.Lock()
+	state, ok := client.reviewStates[prUrl]
+	client.mu.Unlock()
+	if ok {
+		return state, nil
+	}
+
+	reviews := []review{}
+	if err := client.get(ghApiClient, prUrl+"/reviews?per_page=100", &reviews); err != nil {
+		return "", err
+	}
+	latest := map[string]string{}
+	for _, r := range reviews {
+		// comments don't change a reviewer's verdict
+		if r.State == "COMMENTED" {
+			continue
+		}
+		latest[r.User.Login] = strings.ToLower(r.State)
+	}
+	for _, s := range latest {
+		if s == ReviewChangesRequested {
+			state = ReviewChangesRequested
+			break
+		}
+		if s == ReviewApproved {
+			state = ReviewApproved
+		}
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.reviewStates == nil {
+		client.reviewStates = map[string]string{}
+	}
+	client.reviewStates[prUrl] = state
+	return state, nil
+}
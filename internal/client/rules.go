@@ -0,0 +1,97 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A deleteRule is a parsed --delete-when expression: a comma separated list
+// of key=value conditions that all have to hold for a notification to be
+// deleted, e.g. "reason=subscribed,state=closed".
+type deleteRule []condition
+
+type condition struct {
+	key   string
+	value string
+}
+
+// ruleKeys maps the keys allowed in --delete-when to the notification
+// property they are compared against.
+var ruleKeys = map[string]func(NotificationResult) string{
+	"reason": func(r NotificationResult) string { return r.Notification.Reason },
+	"type":   func(r NotificationResult) string { return r.Notification.Subject.Type },
+	"repo":   func(r NotificationResult) string { return r.Notification.Repository.FullName },
+	"unread": func(r NotificationResult) string { return strconv.FormatBool(r.Notification.Unread) },
+	"state": func(r NotificationResult) string {
+		if r.PR != nil {
+			return r.PR.State
+		}
+		if r.Issue != nil {
+			return r.Issue.State
+		}
+		return ""
+	},
+	"author": func(r NotificationResult) string {
+		if r.PR == nil {
+			return ""
+		}
+		return r.PR.User.Login
+	},
+}
+
+func ruleKeyNames() []string {
+	return []string{"reason", "type", "repo", "unread", "state", "author"}
+}
+
+func parseDeleteRule(expr string) (deleteRule, error) {
+	rule := deleteRule{}
+	for _, part := range strings.Split(expr, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --delete-when condition %q in %q, expected key=value", part, expr)
+		}
+		c, err := newCondition(key, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --delete-when %q: %w", expr, err)
+		}
+		rule = append(rule, c)
+	}
+	return rule, nil
+}
+
+func newCondition(key, value string) (condition, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+	if _, known := ruleKeys[key]; !known {
+		return condition{}, fmt.Errorf("unknown key %q, expected one of %s", key, strings.Join(ruleKeyNames(), ", "))
+	}
+	if key == "repo" {
+		if err := validateRepoPatterns("repo", []string{value}); err != nil {
+			return condition{}, err
+		}
+	}
+	return condition{key: key, value: value}, nil
+}
+
+func (rule deleteRule) String() string {
+	parts := make([]string, 0, len(rule))
+	for _, c := range rule {
+		parts = append(parts, c.key+"="+c.value)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (rule deleteRule) matches(result NotificationResult) bool {
+	for _, c := range rule {
+		actual := ruleKeys[c.key](result)
+		if c.key == "repo" {
+			if !matchRepo([]string{c.value}, actual) {
+				return false
+			}
+		} else if !strings.EqualFold(actual, c.value) {
+			return false
+		}
+	}
+	return true
+}
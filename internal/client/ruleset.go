@@ -0,0 +1,136 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Actions of the rules in the config file, see --rules.
+const (
+	ActionDelete      = "delete"
+	ActionKeep        = "keep"
+	ActionMarkRead    = "mark-read"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// Modes of --mode, what happens to the matches of the built-in rules,
+// --delete-when and --query. GitHub marks a thread as done when it gets
+// deleted, so done is the same request as delete.
+const (
+	ModeDelete = "delete"
+	ModeDone   = "done"
+	ModeRead   = "read"
+)
+
+// configRule is a rule as written in the config file.
+type configRule struct {
+	Name   string            `yaml:"name"`
+	Match  map[string]string `yaml:"match"`
+	Action string            `yaml:"action"`
+}
+
+// actionRule takes its action on the notifications all of its matchers
+// hold for. Rules are evaluated in order, the first matching one wins.
+type actionRule struct {
+	name       string
+	conditions deleteRule
+	olderThan  time.Duration
+	newerThan  time.Duration
+	title      *regexp.Regexp
+	action     string
+}
+
+// rules parses the rules of the config file.
+func (config *Config) rules() ([]actionRule, error) {
+	rules := make([]actionRule, 0, len(config.Rules))
+	for i, r := range config.Rules {
+		rule := actionRule{name: r.Name, action: r.Action}
+		if rule.name == "" {
+			rule.name = fmt.Sprintf("rule %d", i+1)
+		}
+		switch r.Action {
+		case ActionDelete, ActionKeep, ActionMarkRead, ActionUnsubscribe:
+		default:
+			return nil, fmt.Errorf("invalid action %q in %s, expected %s, %s, %s or %s", r.Action, rule.name, ActionDelete, ActionKeep, ActionMarkRead, ActionUnsubscribe)
+		}
+
+		keys := make([]string, 0, len(r.Match))
+		for key := range r.Match {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := r.Match[key]
+			var err error
+			switch key {
+			case "older_than":
+				rule.olderThan, err = parseAge(value)
+			case "newer_than":
+				rule.newerThan, err = parseAge(value)
+			case "title":
+				rule.title, err = regexp.Compile("(?i)" + value)
+			default:
+				var c condition
+				c, err = newCondition(key, value)
+				rule.conditions = append(rule.conditions, c)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s in %s: %w", key, rule.name, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (rule actionRule) matches(res NotificationResult) bool {
+	age := time.Since(res.Notification.UpdatedAt)
+	switch {
+	case !rule.conditions.matches(res):
+		return false
+	case rule.olderThan > 0 && age < rule.olderThan:
+		return false
+	case rule.newerThan > 0 && age >= rule.newerThan:
+		return false
+	case rule.title != nil && !rule.title.MatchString(res.Notification.Subject.Title):
+		return false
+	}
+	return true
+}
+
+// applyRules takes the action of the first matching --rules rule.
+func (client *Client) applyRules(status *NotificationResult) {
+	for _, rule := range client.opts.actionRules {
+		if !rule.matches(*status) {
+			continue
+		}
+		status.Rule = rule.name
+		switch rule.action {
+		case ActionDelete:
+			status.Deleted = true
+		case ActionMarkRead, ActionUnsubscribe:
+			status.Action = rule.action
+		}
+		return
+	}
+}
+
+// act marks a thread as read or unsubscribes from it, for --rules and
+// --mode read.
+func (client *Client) act(ghApiClient *api.RESTClient, status *NotificationResult) {
+	var err error
+	switch status.Action {
+	case ActionMarkRead:
+		err = client.markRead(ghApiClient, status.Notification)
+	case ActionUnsubscribe:
+		err = client.unsubscribe(ghApiClient, status)
+	}
+	if err != nil {
+		status.Action = ""
+		status.Err = err
+	}
+}
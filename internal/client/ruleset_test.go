@@ -0,0 +1,53 @@
+package client
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestRuleActionsAreConfirmed(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var mu sync.Mutex
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			mu.Lock()
+			requests = append(requests, r.Method+" "+r.URL.Path)
+			mu.Unlock()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	opts := &Options{Select: true, OrderBy: OrderUpdated, actionRules: []actionRule{{name: "quiet", action: ActionUnsubscribe}}}
+	client, api := fakeClient(t, opts, handler)
+
+	for _, id := range []string{"1", "2", "3"} {
+		status := NotificationResult{}
+		status.Notification.Id = id
+		status.Notification.Url = api + "notifications/threads/" + id
+		client.statuses <- status
+	}
+	close(client.statuses)
+	client.wgDeleter.Add(1)
+	client.deleteNotifications()
+	client.finish()
+
+	if pending := client.Pending(); len(pending) != 3 || len(requests) > 0 {
+		t.Fatalf("%d matches pending after %v, want all 3 before any request", len(pending), requests)
+	}
+	client.ApplyPending(func(res NotificationResult) bool { return res.Notification.Id == "2" })
+	var results []NotificationResult
+	for res := range client.results {
+		results = append(results, res)
+	}
+
+	if got := ids(results, func(res NotificationResult) bool { return res.Action == ActionUnsubscribe }); got != "2" {
+		t.Errorf("unsubscribed from %q, want 2", got)
+	}
+	if got := ids(results, func(res NotificationResult) bool { return res.Declined }); got != "1,3" {
+		t.Errorf("declined %q, want 1,3", got)
+	}
+	if len(requests) != 1 || requests[0] != "DELETE /notifications/threads/2/subscription" {
+		t.Errorf("sent %v, want only the unsubscribe of 2", requests)
+	}
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+const (
+	endpointTopics    = "GET repository topics"
+	endpointTeamRepos = "GET team repositories"
+)
+
+// inScope reports whether a repository carries one of the --repo-topic
+// topics and belongs to the --team. Topics are cached per repository, a
+// repository whose topics cannot be fetched is out of scope.
+func (client *Client) inScope(ghApiClient *api.RESTClient, repo string) bool {
+	if client.opts.Team != "" && !client.teamRepos[strings.ToLower(repo)] {
+		return false
+	}
+	if len(client.opts.RepoTopics) == 0 {
+		return true
+	}
+	topics, ok := client.repoTopics[repo]
+	if !ok {
+		response := struct{ Names []string }{}
+		if err := client.get(ghApiClient, "repos/"+repo+"/topics", &response); err != nil {
+			client.recordAPIError(endpointTopics, err)
+		}
+		topics = response.Names
+		if client.repoTopics == nil {
+			client.repoTopics = map[string][]string{}
+		}
+		client.repoTopics[repo] = topics
+	}
+	for _, topic := range client.opts.RepoTopics {
+		if slices.Contains(topics, strings.ToLower(topic)) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTeamRepos fetches the repositories of the --team, given as
+// org/team-slug.
+func (client *Client) loadTeamRepos(ghApiClient *api.RESTClient) error {
+	org, slug, _ := strings.Cut(client.opts.Team, "/")
+	client.teamRepos = map[string]bool{}
+	for page := 1; ; page++ {
+		repos := []struct {
+			FullName string `json:"full_name"`
+		}{}
+		path := fmt.Sprintf("orgs/%s/teams/%s/repos?per_page=100&page=%d", org, slug, page)
+		if err := client.get(ghApiClient, path, &repos); err != nil {
+			return fmt.Errorf("cannot list the repositories of team %s: %w", client.opts.Team, client.recordAPIError(endpointTeamRepos, err))
+		}
+		for _, repo := range repos {
+			client.teamRepos[strings.ToLower(repo.FullName)] = true
+		}
+		if len(repos) < 100 {
+			return nil
+		}
+	}
+}
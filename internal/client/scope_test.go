@@ -0,0 +1,62 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestInScope(t *testing.T) {
+	topicRequests := map[string]int{}
+	mux := http.NewServeMux()
+	for repo, names := range map[string]string{"cli/cli": `["cli", "go"]`, "cli/docs": `[]`} {
+		repo, names := repo, names
+		mux.HandleFunc("/repos/"+repo+"/topics", func(w http.ResponseWriter, r *http.Request) {
+			topicRequests[repo]++
+			fmt.Fprintf(w, `{"names": %s}`, names)
+		})
+	}
+	mux.HandleFunc("/repos/cli/gone/topics", func(w http.ResponseWriter, r *http.Request) {
+		topicRequests["cli/gone"]++
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+	mux.HandleFunc("/orgs/cli/teams/core/repos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"full_name": "cli/cli"}, {"full_name": "CLI/Gone"}]`)
+	})
+
+	tests := []struct {
+		name   string
+		team   string
+		topics []string
+		want   map[string]bool
+	}{
+		{"no scope", "", nil, map[string]bool{"cli/cli": true, "cli/docs": true, "cli/gone": true}},
+		{"topic", "", []string{"Go"}, map[string]bool{"cli/cli": true, "cli/docs": false, "cli/gone": false}},
+		{"team", "cli/core", nil, map[string]bool{"cli/cli": true, "cli/docs": false, "cli/gone": true}},
+		{"team and topic", "cli/core", []string{"go"}, map[string]bool{"cli/cli": true, "cli/docs": false, "cli/gone": false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clear(topicRequests)
+			client, _ := fakeClient(t, &Options{Team: tt.team, RepoTopics: tt.topics}, mux)
+			ghApiClient := client.restClients[client.hosts()[0]]
+			if tt.team != "" {
+				if err := client.loadTeamRepos(ghApiClient); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				for repo, want := range tt.want {
+					if got := client.inScope(ghApiClient, repo); got != want {
+						t.Errorf("inScope(%s) = %v, want %v", repo, got, want)
+					}
+				}
+			}
+			for repo, n := range topicRequests {
+				if n > 1 {
+					t.Errorf("fetched the topics of %s %d times, want them cached", repo, n)
+				}
+			}
+		})
+	}
+}
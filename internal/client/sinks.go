@@ -0,0 +1,58 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// reportFile is an extra rendering of the results written to a file at the
+// end of a run, next to whatever goes to the terminal, see --report-file.
+type reportFile struct {
+	fileName string
+	format   string
+	results  []NotificationResult
+}
+
+// send hands a result to the reader of the results and to the report file,
+// anonymized with --anonymize.
+func (client *Client) send(status NotificationResult) {
+	if client.anonymizer != nil {
+		status = client.anonymizer.result(status)
+	}
+	client.mu.Lock()
+	if client.report != nil {
+		client.report.results = append(client.report.results, status)
+	}
+	if status.Err != nil {
+		client.failed = append(client.failed, status)
+	}
+	client.mu.Unlock()
+	client.results <- status
+}
+
+// writeReportFile renders the collected results into the --report-file.
+func (client *Client) writeReportFile() {
+	if client.report == nil {
+		return
+	}
+	f, err := os.Create(client.report.fileName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write report:", err)
+		return
+	}
+	var render func(io.Writer, []NotificationResult) error
+	switch client.report.format {
+	case FormatMarkdown:
+		render = client.writeMarkdown
+	default:
+		render = client.writeJSON
+	}
+	err = render(f, client.report.results)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush: cannot write report:", err)
+	}
+}
@@ -0,0 +1,72 @@
+package client
+
+import "fmt"
+
+// streaming reports whether notifications are processed while the later
+// pages are still being fetched. Some options need all of them up front.
+func (client *Client) streaming() bool {
+	opts := client.opts
+	return opts.Estimate == 0 && opts.DumpNotifications == "" && opts.subject == nil && !opts.ListTypes &&
+		opts.Sample == 0 && opts.Limit == 0 && opts.OrderBy == OrderUpdated
+}
+
+// startStream fetches the first page and leaves the rest to a goroutine
+// that feeds each page to the workers as it arrives. A failure after the
+// first page stops fetching, what was fetched is still flushed but the run
+// exits with ExitError.
+func (client *Client) startStream() error {
+	client.streamed = true
+	started := make(chan error, 1)
+	go func() {
+		defer close(client.input)
+		defer client.fetchDone.Store(true)
+		first := true
+		seen := map[string]bool{}
+		for _, host := range client.hosts() {
+			err := client.fetchNotifications(host, func(page []Notification) {
+				if first {
+					first = false
+					started <- nil
+				}
+				unique := make([]Notification, 0, len(page))
+				for _, notification := range page {
+					// the inbox can change while paging, the first copy of a
+					// thread is already on its way
+					if seen[notification.Url] {
+						client.duplicates++
+						continue
+					}
+					seen[notification.Url] = true
+					unique = append(unique, notification)
+				}
+				if !client.opts.RESTLookups {
+					client.prefetchPullRequests(unique)
+				}
+				client.notifications = append(client.notifications, unique...)
+				client.numFetched.Add(int64(len(unique)))
+				for _, notification := range unique {
+					client.input <- notification
+				}
+			})
+			if err != nil && first {
+				started <- err
+				return
+			}
+			if err != nil {
+				client.fetchErr = err
+				client.truncated = fmt.Sprintf("stopped after an error: %v", err)
+				return
+			}
+		}
+		if first {
+			started <- nil
+		}
+	}()
+	return <-started
+}
+
+// FetchDone reports whether all notifications were fetched, so that
+// NotificationCount is final.
+func (client *Client) FetchDone() bool {
+	return client.fetchDone.Load()
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDefaultOptionsStream(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	var api string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"id": "1", "url": "%[1]snotifications/threads/1", "unread": true, "subject": {"type": "Release"}},
+			{"id": "2", "url": "%[1]snotifications/threads/2", "unread": true, "subject": {"type": "Release"}}]`, api)
+	})
+	// the defaults of the flags that streaming depends on
+	client, api := fakeClient(t, &Options{OrderBy: OrderUpdated, DryRun: true}, handler)
+
+	if err := client.FetchNotifications(); err != nil {
+		t.Fatal(err)
+	}
+	client.ProcessNotifications()
+	results := 0
+	for {
+		if _, ok := client.GetNotificationResult(); !ok {
+			break
+		}
+		results++
+	}
+
+	if !client.streamed {
+		t.Error("notifications were not streamed with the default options")
+	}
+	if results != 2 || client.NotificationCount() != 2 || !client.FetchDone() {
+		t.Errorf("got %d results of %d notifications, done: %v, want 2 of 2", results, client.NotificationCount(), client.FetchDone())
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// subjectRef points at a single issue or pull request.
+type subjectRef struct {
+	repo   string
+	number int
+}
+
+var (
+	shorthandRE = regexp.MustCompile(`^([\w.-]+/[\w.-]+)#(\d+)$`)
+	// matches both html (owner/repo/pull/1) and API (repos/owner/repo/pulls/1) paths
+	subjectPathRE = regexp.MustCompile(`^/(?:api/v3/)?(?:repos/)?([\w.-]+/[\w.-]+)/(?:pull|pulls|issues)/(\d+)/?$`)
+)
+
+// parseSubjectRef understands owner/repo#123 as well as html and API URLs of
+// issues and pull requests.
+func parseSubjectRef(s string) (*subjectRef, error) {
+	s = strings.TrimSpace(s)
+	m := shorthandRE.FindStringSubmatch(s)
+	if m == nil {
+		if u, err := url.Parse(s); err == nil && u.Host != "" {
+			m = subjectPathRE.FindStringSubmatch(u.Path)
+		}
+	}
+	if m == nil {
+		return nil, fmt.Errorf("invalid --subject %q, expected owner/repo#123 or an issue or pull request URL", s)
+	}
+	number, err := strconv.Atoi(m[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid --subject %q: %w", s, err)
+	}
+	return &subjectRef{repo: m[1], number: number}, nil
+}
+
+func (ref *subjectRef) String() string {
+	return fmt.Sprintf("%s#%d", ref.repo, ref.number)
+}
+
+// matches compares against the notification's subject API URL, which ends in
+// .../repos/owner/repo/pulls/123 or .../issues/123.
+func (ref *subjectRef) matches(notification Notification) bool {
+	u, err := url.Parse(notification.Subject.Url)
+	if err != nil {
+		return false
+	}
+	m := subjectPathRE.FindStringSubmatch(u.Path)
+	return m != nil && strings.EqualFold(m[1], ref.repo) && m[2] == strconv.Itoa(ref.number)
+}
+
+// Host is the GitHub host the notification comes from, or an empty string
+// for the default host if it isn't known.
+func (n Notification) Host() string {
+	u, err := url.Parse(n.Url)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Host == "api.github.com" {
+		return "github.com"
+	}
+	// GitHub Enterprise Server serves the API under /api/v3
+	return u.Host
+}
+
+// HTMLUrl turns the subject's API URL into the URL of its page on GitHub,
+// or returns an empty string if the notification has no subject URL.
+func (n Notification) HTMLUrl() string {
+	u, err := url.Parse(n.Subject.Url)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Host == "api.github.com" {
+		u.Host = "github.com"
+	}
+	path := strings.TrimPrefix(u.Path, "/api/v3")
+	path = strings.TrimPrefix(path, "/repos")
+	path = strings.Replace(path, "/pulls/", "/pull/", 1)
+	path = strings.Replace(path, "/commits/", "/commit/", 1)
+	u.Path = path
+	return u.String()
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Thread subscriptions, see --show-subscription.
+const (
+	SubscriptionSubscribed = "subscribed"
+	SubscriptionWatching   = "watching"
+	SubscriptionIgnored    = "ignored"
+)
+
+const (
+	endpointUnsubscribe = "DELETE subscription"
+	endpointResubscribe = "PUT subscription"
+)
+
+// subscription looks up whether the user is subscribed to a thread itself,
+// gets it only by watching the repository, or ignores it.
+func (client *Client) subscription(ghApiClient *api.RESTClient, notification Notification) (string, error) {
+	subscription := struct {
+		Subscribed bool
+		Ignored    bool
+	}{}
+	err := client.get(ghApiClient, notification.Url+"/subscription", &subscription)
+	var httpErr *api.HTTPError
+	switch {
+	case errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound:
+		// no subscription to the thread itself
+		return SubscriptionWatching, nil
+	case err != nil:
+		return "", err
+	case subscription.Ignored:
+		return SubscriptionIgnored, nil
+	case subscription.Subscribed:
+		return SubscriptionSubscribed, nil
+	}
+	return SubscriptionWatching, nil
+}
+
+// unsubscribe deletes the subscription to a thread, so new comments don't
+// bring the notification back. The subscription it had is kept in
+// PriorSubscription so that it can be restored.
+func (client *Client) unsubscribe(ghApiClient *api.RESTClient, status *NotificationResult) error {
+	prior := status.Subscription
+	if prior == "" {
+		var err error
+		if prior, err = client.subscription(ghApiClient, status.Notification); err != nil {
+			// unsubscribing still works, it just cannot be undone
+			client.recordAPIError(endpointSubscription, err)
+		}
+	}
+	if _, err := client.mutate(ghApiClient, http.MethodDelete, status.Notification.Url+"/subscription", nil); err != nil {
+		return client.recordAPIError(endpointUnsubscribe, err)
+	}
+	status.PriorSubscription = prior
+	return nil
+}
+
+// Reversible reports whether what was done to a notification can be undone
+// with Resubscribe. Deleting a thread and marking it as read cannot be
+// undone, unsubscribing from it can.
+func (client *Client) Reversible(res NotificationResult) bool {
+	unsubscribed := res.Action == ActionUnsubscribe || res.Unsubscribed
+	return unsubscribed && res.PriorSubscription != "" && res.Err == nil && !res.Simulated && !client.opts.DryRun
+}
+
+// Resubscribe restores the subscription to the thread of a notification
+// that it had before unsubscribing.
+func (client *Client) Resubscribe(res NotificationResult) error {
+	ghApiClient, err := client.restClient(res.Notification.Host())
+	if err != nil {
+		return err
+	}
+	endpoint, method, body := endpointResubscribe, http.MethodPut, []byte(`{"ignored": false}`)
+	switch res.PriorSubscription {
+	case SubscriptionIgnored:
+		body = []byte(`{"ignored": true}`)
+	case SubscriptionWatching:
+		// only watching the repository, there was no subscription to the
+		// thread itself
+		endpoint, method, body = endpointUnsubscribe, http.MethodDelete, nil
+	}
+	if _, err := client.mutate(ghApiClient, method, res.Notification.Url+"/subscription", body); err != nil {
+		return client.recordAPIError(endpoint, err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestResubscribeRestoresPriorState(t *testing.T) {
+	priors := []struct {
+		state    string
+		status   int
+		body     string
+		wantUndo string
+	}{
+		{SubscriptionSubscribed, http.StatusOK, `{"subscribed": true}`, `PUT {"ignored": false}`},
+		{SubscriptionIgnored, http.StatusOK, `{"ignored": true}`, `PUT {"ignored": true}`},
+		{SubscriptionWatching, http.StatusNotFound, `{"message": "Not Found"}`, `DELETE `},
+	}
+	paths := []struct {
+		name  string
+		opts  Options
+		match func(*NotificationResult)
+	}{
+		{"rule", Options{}, func(res *NotificationResult) { res.Action = ActionUnsubscribe }},
+		{"--unsubscribe", Options{Unsubscribe: true}, func(res *NotificationResult) { res.Deleted = true }},
+	}
+	for _, path := range paths {
+		for _, prior := range priors {
+			t.Run(path.name+" "+prior.state, func(t *testing.T) {
+				t.Setenv("XDG_STATE_HOME", t.TempDir())
+				var last string
+				handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Method == http.MethodGet {
+						w.WriteHeader(prior.status)
+						io.WriteString(w, prior.body)
+						return
+					}
+					if r.URL.Path == "/notifications/threads/1/subscription" {
+						body, _ := io.ReadAll(r.Body)
+						last = r.Method + " " + string(body)
+					}
+					w.WriteHeader(http.StatusNoContent)
+				})
+				opts := path.opts
+				client, api := fakeClient(t, &opts, handler)
+				res := NotificationResult{}
+				res.Notification.Url = api + "notifications/threads/1"
+				path.match(&res)
+
+				client.apply(client.restClients[client.hosts()[0]], &res)
+				if res.PriorSubscription != prior.state || !client.Reversible(res) {
+					t.Fatalf("PriorSubscription = %q, Reversible = %v, want %q and true", res.PriorSubscription, client.Reversible(res), prior.state)
+				}
+				if err := client.Resubscribe(res); err != nil {
+					t.Fatal(err)
+				}
+				if last != prior.wantUndo {
+					t.Errorf("undid with %q, want %q", last, prior.wantUndo)
+				}
+			})
+		}
+	}
+}
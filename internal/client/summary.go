@@ -0,0 +1,245 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// AgeBucket counts flushed and kept notifications last updated within an
+// age range.
+type AgeBucket struct {
+	Label   string
+	MaxAge  time.Duration
+	Flushed int
+	Kept    int
+}
+
+// AgeHistogram groups results by how long ago they were updated.
+func AgeHistogram(results []NotificationResult, now time.Time) []AgeBucket {
+	buckets := []AgeBucket{
+		{Label: "<1d", MaxAge: day},
+		{Label: "1-7d", MaxAge: 7 * day},
+		{Label: "7-30d", MaxAge: 30 * day},
+		{Label: "30-90d", MaxAge: 90 * day},
+		{Label: ">90d"},
+	}
+	for _, res := range results {
+		age := now.Sub(res.Notification.UpdatedAt)
+		i := 0
+		for i < len(buckets)-1 && age >= buckets[i].MaxAge {
+			i++
+		}
+		if res.Deleted {
+			buckets[i].Flushed++
+		} else {
+			buckets[i].Kept++
+		}
+	}
+	return buckets
+}
+
+// HistogramBars renders flushed and kept counts as block character bars
+// scaled so that the largest bucket is width characters wide.
+func HistogramBars(buckets []AgeBucket, width int) (flushed, kept []string) {
+	largest := 0
+	for _, b := range buckets {
+		largest = max(largest, b.Flushed+b.Kept)
+	}
+	scale := func(n int) int {
+		if largest == 0 || n == 0 {
+			return 0
+		}
+		return max(1, n*width/largest)
+	}
+	for _, b := range buckets {
+		flushed = append(flushed, strings.Repeat("█", scale(b.Flushed)))
+		kept = append(kept, strings.Repeat("░", scale(b.Kept)))
+	}
+	return flushed, kept
+}
+
+// LabelWidth is the width of the longest bucket label, to line up the bars.
+func LabelWidth(buckets []AgeBucket) int {
+	width := 0
+	for _, b := range buckets {
+		width = max(width, len(b.Label))
+	}
+	return width
+}
+
+func formatAgeHistogram(buckets []AgeBucket) string {
+	var sb strings.Builder
+	sb.WriteString("Age of notifications (█ flushed, ░ kept):\n")
+	flushed, kept := HistogramBars(buckets, 40)
+	width := LabelWidth(buckets)
+	for i, b := range buckets {
+		fmt.Fprintf(&sb, "%-*s %s%s %d flushed, %d kept\n", width, b.Label, flushed[i], kept[i], b.Flushed, b.Kept)
+	}
+	return sb.String()
+}
+
+// RepoCount counts flushed and kept notifications of one repository.
+type RepoCount struct {
+	Repo    string
+	Flushed int
+	Kept    int
+	// KeptFor counts the kept notifications by keptReason.
+	KeptFor map[string]int
+}
+
+// RepoSummary groups results by repository, the repositories with the most
+// flushed notifications first.
+func RepoSummary(results []NotificationResult) []RepoCount {
+	byRepo := map[string]*RepoCount{}
+	for _, res := range results {
+		name := res.Notification.Repository.FullName
+		count, ok := byRepo[name]
+		if !ok {
+			count = &RepoCount{Repo: name, KeptFor: map[string]int{}}
+			byRepo[name] = count
+		}
+		if res.Deleted {
+			count.Flushed++
+		} else {
+			count.Kept++
+			count.KeptFor[keptReason(res)]++
+		}
+	}
+	counts := make([]RepoCount, 0, len(byRepo))
+	for _, count := range byRepo {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Flushed != counts[j].Flushed {
+			return counts[i].Flushed > counts[j].Flushed
+		}
+		return counts[i].Repo < counts[j].Repo
+	})
+	return counts
+}
+
+// FormatReasonSummary counts the flushed and kept notifications per reason,
+// most frequent first, under their labels.
+func (client *Client) FormatReasonSummary(results []NotificationResult) string {
+	flushed := map[string]int{}
+	kept := map[string]int{}
+	labels := []string{}
+	width := 0
+	for _, res := range results {
+		label := client.ReasonLabel(res.Notification.Reason)
+		if flushed[label]+kept[label] == 0 {
+			labels = append(labels, label)
+			width = max(width, len(label))
+		}
+		if res.Deleted {
+			flushed[label]++
+		} else {
+			kept[label]++
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := flushed[labels[i]]+kept[labels[i]], flushed[labels[j]]+kept[labels[j]]
+		if a != b {
+			return a > b
+		}
+		return labels[i] < labels[j]
+	})
+	var sb strings.Builder
+	sb.WriteString("Per reason:\n")
+	for _, label := range labels {
+		fmt.Fprintf(&sb, "  %-*s %d flushed, %d kept\n", width+1, label+":", flushed[label], kept[label])
+	}
+	return sb.String()
+}
+
+// FormatHostSummary counts the flushed and kept notifications per host,
+// for runs with more than one --hostname.
+func FormatHostSummary(results []NotificationResult) string {
+	flushed := map[string]int{}
+	kept := map[string]int{}
+	hosts := []string{}
+	for _, res := range results {
+		host := res.Notification.Host()
+		if flushed[host]+kept[host] == 0 {
+			hosts = append(hosts, host)
+		}
+		if res.Deleted {
+			flushed[host]++
+		} else {
+			kept[host]++
+		}
+	}
+	sort.Strings(hosts)
+	var sb strings.Builder
+	sb.WriteString("Per host:\n")
+	for _, host := range hosts {
+		fmt.Fprintf(&sb, "  %s: %d flushed, %d kept\n", host, flushed[host], kept[host])
+	}
+	return sb.String()
+}
+
+// keptReason sums up why a notification wasn't flushed.
+func keptReason(res NotificationResult) string {
+	switch {
+	case res.Err != nil:
+		return "failed"
+	case res.Declined:
+		return "declined"
+	case res.KeptRecent:
+		return "recent"
+	case res.Active:
+		return "active"
+	case res.Labeled:
+		return "labeled"
+	case res.RecentMention:
+		return "recent mention"
+	case res.Fresh:
+		return "fresh"
+	case res.Limited:
+		return "over limit"
+	case res.Protected:
+		return "protected"
+	case res.Excluded:
+		return "excluded"
+	default:
+		return "no rule matched"
+	}
+}
+
+// FormatUnflushedRepos lists the repositories where nothing was flushed,
+// with why their notifications were kept, for --show-unflushed-repos.
+func FormatUnflushedRepos(results []NotificationResult) string {
+	var sb strings.Builder
+	for _, repo := range RepoSummary(results) {
+		if repo.Flushed > 0 {
+			continue
+		}
+		reasons := make([]string, 0, len(repo.KeptFor))
+		for reason := range repo.KeptFor {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			if repo.KeptFor[reasons[i]] != repo.KeptFor[reasons[j]] {
+				return repo.KeptFor[reasons[i]] > repo.KeptFor[reasons[j]]
+			}
+			return reasons[i] < reasons[j]
+		})
+		for i, reason := range reasons {
+			reasons[i] = fmt.Sprintf("%d %s", repo.KeptFor[reason], reason)
+		}
+		fmt.Fprintf(&sb, "  %s: %d kept (%s)\n", repo.Repo, repo.Kept, strings.Join(reasons, ", "))
+	}
+	if sb.Len() == 0 {
+		return "Repositories with nothing flushed: none\n"
+	}
+	return "Repositories with nothing flushed:\n" + sb.String()
+}
+
+// ShowUnflushedRepos reports whether --show-unflushed-repos was given.
+func (client *Client) ShowUnflushedRepos() bool {
+	return client.opts.ShowUnflushedRepos
+}
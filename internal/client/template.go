@@ -0,0 +1,85 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+// namedTemplates can be passed to --template by name.
+var namedTemplates = map[string]string{
+	"compact": `{{.Action}} {{.Repo}}: {{.Title}}`,
+	"tsv":     `{{.UpdatedAt.Format "2006-01-02T15:04:05Z07:00"}}	{{.Action}}	{{.Repo}}	{{.Title}}	{{join .Tags ","}}`,
+	"links":   `{{if .Deleted}}{{.Url}}{{end}}`,
+}
+
+// TemplateResult is what a --template is executed with: the same fields as
+// the JSON output plus a few conveniences.
+type TemplateResult struct {
+	JSONNotification
+	// Notification is the notification as GitHub returned it, like
+	// {{.Notification.Repository.FullName}}.
+	Notification Notification
+	// Action is what happened to the notification: deleted, dry-run, kept
+	// or failed.
+	Action string
+	Age    time.Duration
+	// Ago is the age in words, like "3 days ago".
+	Ago string
+	// ReasonLabel is the reason in words, like "Review requested".
+	ReasonLabel string
+}
+
+var templateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+func parseOutputTemplate(text string) (*template.Template, error) {
+	if named, ok := namedTemplates[text]; ok {
+		text = named
+	}
+	tmpl, err := template.New("output").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (client *Client) newTemplateResult(res NotificationResult) TemplateResult {
+	n := client.newJSONNotification(res)
+	action := "kept"
+	switch {
+	case n.Error != "":
+		action = "failed"
+	case n.DryRun:
+		action = "dry-run"
+	case n.Deleted:
+		action = "deleted"
+	}
+	return TemplateResult{
+		JSONNotification: n,
+		Notification:     res.Notification,
+		Action:           action,
+		Age:              time.Since(n.UpdatedAt),
+		Ago:              humanize.Time(n.UpdatedAt),
+		ReasonLabel:      client.ReasonLabel(n.Reason),
+	}
+}
+
+func (client *Client) printTemplate() {
+	result, ok := client.GetNotificationResult()
+	for ok {
+		if err := client.opts.template.Execute(client.out, client.newTemplateResult(result)); err != nil {
+			client.out.Flush()
+			exitWithError(fmt.Errorf("cannot render --template: %w", err))
+		}
+		fmt.Fprintln(client.out)
+		client.flushLine()
+		result, ok = client.GetNotificationResult()
+	}
+}
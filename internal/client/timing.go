@@ -0,0 +1,49 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// slowestShown is how many of the slowest notifications --verbose lists.
+const slowestShown = 5
+
+// timed runs call and, with --verbose, adds its wall time to d.
+func (client *Client) timed(d *time.Duration, call func() error) error {
+	if !client.opts.Verbose {
+		return call()
+	}
+	start := time.Now()
+	err := call()
+	*d += time.Since(start)
+	return err
+}
+
+// FormatSlowest lists the notifications that took the longest to fetch and
+// delete, only --verbose runs measure them.
+func FormatSlowest(results []NotificationResult) string {
+	timed := []NotificationResult{}
+	for _, res := range results {
+		if res.FetchTime+res.DeleteTime > 0 {
+			timed = append(timed, res)
+		}
+	}
+	if len(timed) == 0 {
+		return ""
+	}
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].FetchTime+timed[i].DeleteTime > timed[j].FetchTime+timed[j].DeleteTime
+	})
+	var sb strings.Builder
+	sb.WriteString("Slowest notifications:\n")
+	for _, res := range timed[:min(slowestShown, len(timed))] {
+		fmt.Fprintf(&sb, "  %s [%s] %s\n", formatTiming(res), res.Notification.Repository.FullName, res.Notification.Subject.Title)
+	}
+	return sb.String()
+}
+
+func formatTiming(res NotificationResult) string {
+	return fmt.Sprintf("fetch %s, delete %s", res.FetchTime.Round(time.Millisecond), res.DeleteTime.Round(time.Millisecond))
+}
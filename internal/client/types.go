@@ -1,58 +1,242 @@
 package client
 
 import (
+	"bufio"
+	"os"
 	"sync"
+	"sync/atomic"
+	"text/template"
 	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
 )
 
 type Client struct {
 	opts          *Options
 	notifications []Notification
+	truncated     string
+	fetchErr      error
+	comparison    string
+	drift         int
+	estimate      *estimate
+	out           *bufio.Writer
+	report        *reportFile
+	anonymizer    *anonymizer
+	duplicates    int
 	input         chan Notification
 	statuses      chan NotificationResult
 	results       chan NotificationResult
 	wgFetcher     *sync.WaitGroup
 	wgDeleter     *sync.WaitGroup
+	deletePacer   *pacer
+	workerGate    *workerGate
+	logins        map[string]*hostLogin
+	restClients   map[string]*api.RESTClient
+	fetchStarted  map[string]time.Time
+	failed        []NotificationResult
+	rateLimits    rateLimiter
+	prefetched    map[string]*PullRequest
+	numFetched    atomic.Int64
+	fetchDone     atomic.Bool
+	streamed      bool
+	numProcessed  atomic.Int64
+	numDeleted    atomic.Int64
+	numMatched    atomic.Int64
+	mu            sync.Mutex
+	failures      []failedDeletion
+	apiErrors     APIErrorSummary
+	activity      []string
+	// commit log, see commitlog.go
+	commitMu       sync.Mutex
+	commitLog      *os.File
+	alreadyDeleted map[string]bool
+	held           []NotificationResult
+	pending        []NotificationResult
+	reviewStates   map[string]string
+	issues         map[string]*Issue
+	// latest comment author by subject URL
+	lastCommenters map[string]string
+	// --check-permissions
+	permissionsChecked int
+	permissionChecks   []permissionCheck
+	promptOnStderr     bool
+	repoTopics         map[string][]string
+	teamRepos          map[string]bool
 }
 
 type Notification struct {
-	Id         string
-	Reason     string
-	Url        string
-	Unread     bool
-	UpdatedAt  time.Time `json:"updated_at"`
+	Id         string     `json:"id"`
+	Reason     string     `json:"reason"`
+	Url        string     `json:"url"`
+	Unread     bool       `json:"unread"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	LastReadAt *time.Time `json:"last_read_at"`
 	Repository struct {
 		FullName string `json:"full_name"`
-	}
+	} `json:"repository"`
 	Subject struct {
-		Title string
-		Url   string
-		Type  string
-	}
+		Title            string `json:"title"`
+		Url              string `json:"url"`
+		LatestCommentUrl string `json:"latest_comment_url"`
+		Type             string `json:"type"`
+	} `json:"subject"`
 }
 
 type NotificationResult struct {
-	Notification Notification
-	PR           *PullRequest
-	Deleted      bool
-	Read         bool
-	BotPR        bool
-	ClosedPR     bool
+	Notification        Notification
+	PR                  *PullRequest
+	Issue               *Issue
+	Deleted             bool
+	Read                bool
+	BotPR               bool
+	ClosedPR            bool
+	ClosedIssue         bool
+	Unsubscribed        bool
+	MergedPR            bool
+	OwnPR               bool
+	StaleDraft          bool
+	ObsoleteStateChange bool
+	Commit              bool
+	NewActivity         bool
+	ReviewState         string
+	Subscription        string
+	PriorSubscription   string
+	LastCommentMine     bool
+	Protected           bool
+	KeptRecent          bool
+	Active              bool
+	Labeled             bool
+	RecentMention       bool
+	Old                 bool
+	Fresh               bool
+	Rule                string
+	Action              string
+	Limited             bool
+	Simulated           bool
+	Excluded            bool
+	Declined            bool
+	RenamedFrom         string
+	FetchTime           time.Duration
+	DeleteTime          time.Duration
+	Err                 error
 }
 
 type PullRequest struct {
-	State string
-	User  struct {
+	State          string
+	Merged         bool
+	Draft          bool
+	UpdatedAt      time.Time `json:"updated_at"`
+	Comments       int
+	ReviewComments int `json:"review_comments"`
+	Labels         []Label
+	User           struct {
 		Login string
 		Type  string
 	}
+	Base struct {
+		Repo struct {
+			FullName string `json:"full_name"`
+		}
+	}
+}
+
+// CommentCount counts both conversation and review comments.
+func (pr *PullRequest) CommentCount() int {
+	return pr.Comments + pr.ReviewComments
 }
 
 type Options struct {
 	SkipPRsFromBots       bool
 	SkipClosedPRs         bool
+	SkipUnmergedClosed    bool
+	SkipClosedIssues      bool
 	SkipReadNotifications bool
+	FlushOwnMerged        bool
+	FlushStaleDrafts      bool
+	FlushStateChanges     bool
+	FlushCommitComments   bool
+	CommitCommentAge      time.Duration
+	StaleDraftAge         time.Duration
+	ConfirmPerRepo        bool
+	InteractiveConfirm    bool
+	Review                bool
+	Select                bool
+	UndoWindow            time.Duration
 	DryRun                bool
+	dryRunBy              string
+	CheckPermissions      bool
+	ReadOnly              bool
+	Verbose               bool
+	Quiet                 bool
+	Progress              bool
 	NumWorkers            int
+	DeleteRate            float64
+	AutoWorkers           bool
 	HaltAfter             int
+	Incremental           bool
+	offsets               map[string]time.Time
+	MaxPages              int
+	PerPage               int
+	RESTLookups           bool
+	Before                time.Time
+	Since                 time.Time
+	Subject               string
+	Participating         bool
+	Repos                 []string
+	ExcludeRepos          []string
+	RepoTopics            []string
+	Team                  string
+	ProtectRepos          []string
+	ProtectLabels         []string
+	DeleteWhen            []string
+	Queries               []string
+	deleteRules           []deleteRule
+	Rules                 bool
+	Mode                  string
+	Hostnames             []string
+	Unsubscribe           bool
+	actionRules           []actionRule
+	reasonLabels          map[string]string
+	Sample                int
+	Estimate              float64
+	Seed                  int64
+	KeepRecentPerRepo     int
+	Limit                 int
+	OrderBy               string
+	ActiveThreshold       int
+	KeepMentionedWithin   time.Duration
+	OlderThan             time.Duration
+	NewerThan             time.Duration
+	UnreadSinceRead       bool
+	SkipChangesRequested  bool
+	SkipLastCommentedByMe bool
+	ProgressStyle         string
+	ShowSubscription      bool
+	Format                string
+	JSONFields            []string
+	ReportFile            string
+	ReportFormat          string
+	Template              string
+	DumpNotifications     string
+	template              *template.Template
+	StrictNothing         bool
+	FailOnAnyError        bool
+	Summary               bool
+	Anonymize             bool
+	Bell                  bool
+	NotifyDesktop         bool
+	ShowUnflushedRepos    bool
+	subject               *subjectRef
+	ListTypes             bool
+	ResumeFailed          bool
+	Continue              bool
+	Plan                  bool
+	ApplyPlan             string
+	VerifyPlan            string
+	MaxDrift              int
+	AutoBackup            bool
+	PurgeBackupsOlderThan time.Duration
+	Hook                  string
+	HookTiming            string
+	HookTimeout           time.Duration
 }
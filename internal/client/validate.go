@@ -0,0 +1,80 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// validateOptions rejects invalid values and combinations of flags that
+// contradict each other, before anything talks to GitHub.
+func validateOptions(opts *Options) error {
+	switch opts.Format {
+	case FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatMarkdown:
+	default:
+		return fmt.Errorf("invalid --format %q, expected %s, %s, %s, %s or %s", opts.Format, FormatTable, FormatJSON, FormatNDJSON, FormatCSV, FormatMarkdown)
+	}
+	if opts.OrderBy != OrderUpdated && opts.OrderBy != OrderRepoVolume {
+		return fmt.Errorf("invalid --order-by %q, expected %s or %s", opts.OrderBy, OrderUpdated, OrderRepoVolume)
+	}
+	switch opts.ProgressStyle {
+	case ProgressBar, ProgressPercentage, ProgressSpinner, ProgressNone:
+	default:
+		return fmt.Errorf("invalid --progress-style %q, expected %s, %s, %s or %s", opts.ProgressStyle, ProgressBar, ProgressPercentage, ProgressSpinner, ProgressNone)
+	}
+	if opts.ReportFormat != FormatJSON && opts.ReportFormat != FormatMarkdown {
+		return fmt.Errorf("invalid --report-format %q, expected %s or %s", opts.ReportFormat, FormatJSON, FormatMarkdown)
+	}
+	switch opts.Mode {
+	case ModeDelete, ModeDone, ModeRead:
+	default:
+		return fmt.Errorf("invalid --mode %q, expected %s, %s or %s", opts.Mode, ModeDelete, ModeDone, ModeRead)
+	}
+	if opts.HookTiming != HookEach && opts.HookTiming != HookEnd {
+		return fmt.Errorf("invalid --hook-timing %q, expected %s or %s", opts.HookTiming, HookEach, HookEnd)
+	}
+	if err := validateJSONFields(opts.JSONFields); err != nil {
+		return err
+	}
+	if opts.Limit < 0 || opts.Sample < 0 {
+		return errors.New("--limit and --sample must not be negative")
+	}
+	if org, slug, ok := strings.Cut(opts.Team, "/"); opts.Team != "" && (!ok || org == "" || slug == "") {
+		return fmt.Errorf("invalid --team %q, expected org/team-slug", opts.Team)
+	}
+	if opts.PerPage < 1 || opts.PerPage > 100 {
+		return fmt.Errorf("invalid --per-page %d, expected a number between 1 and 100", opts.PerPage)
+	}
+	if opts.Estimate < 0 || opts.Estimate > 100 {
+		return fmt.Errorf("invalid --estimate %g, expected a percentage between 0 and 100", opts.Estimate)
+	}
+
+	customRules := len(opts.DeleteWhen) > 0 || len(opts.Queries) > 0 || opts.Rules || opts.FlushOwnMerged
+	conflicts := []struct {
+		conflict bool
+		message  string
+	}{
+		{opts.Plan && opts.ApplyPlan != "", "--plan and --apply-plan cannot be combined"},
+		{opts.VerifyPlan != "" && (opts.Plan || opts.ApplyPlan != ""), "--verify-plan cannot be combined with --plan or --apply-plan"},
+		{opts.Review && (opts.ConfirmPerRepo || opts.InteractiveConfirm), "--interactive cannot be combined with --confirm-per-repo or --interactive-confirm"},
+		{opts.Select && (opts.Review || opts.ConfirmPerRepo || opts.InteractiveConfirm), "--select cannot be combined with --interactive, --confirm-per-repo or --interactive-confirm"},
+		{opts.ResumeFailed && opts.ApplyPlan != "", "--resume-failed and --apply-plan cannot be combined"},
+		{opts.ResumeFailed && opts.Continue, "--resume-failed and --continue cannot be combined"},
+		{len(opts.JSONFields) > 0 && opts.Format != FormatJSON && opts.Format != FormatNDJSON, "--json-fields requires --format json or ndjson"},
+		{opts.Template != "" && opts.Format != FormatTable, "--template replaces --format, pass only one of them"},
+		{opts.Mode == ModeRead && (opts.Plan || opts.ApplyPlan != "" || opts.ResumeFailed), "--mode read cannot be combined with --plan, --apply-plan or --resume-failed"},
+		{opts.Subject != "" && len(opts.Repos) > 0, "--subject picks the repository itself, it cannot be combined with --repo"},
+		{opts.Seed != 0 && opts.Sample == 0, "--seed requires --sample"},
+		{opts.Quiet && opts.Verbose, "--quiet and --verbose cannot be combined"},
+		{opts.Rules && (len(opts.DeleteWhen) > 0 || len(opts.Queries) > 0), "--rules cannot be combined with --delete-when or --query"},
+		{opts.FlushOwnMerged && (opts.Rules || len(opts.DeleteWhen) > 0 || len(opts.Queries) > 0), "--flush-own-merged cannot be combined with --delete-when, --query or --rules"},
+		{customRules && (opts.SkipPRsFromBots || opts.SkipClosedPRs || opts.SkipUnmergedClosed || opts.SkipClosedIssues || opts.SkipReadNotifications),
+			"--skip-bots, --skip-closed, --skip-unmerged-closed, --skip-closed-issues and --skip-read only apply to the built-in rules, not to --delete-when, --query, --rules or --flush-own-merged"},
+	}
+	for _, c := range conflicts {
+		if c.conflict {
+			return errors.New(c.message)
+		}
+	}
+	return nil
+}
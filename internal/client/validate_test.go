@@ -0,0 +1,53 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     func(*Options)
+		wantErr string
+	}{
+		{"defaults", func(*Options) {}, ""},
+		{"format", func(o *Options) { o.Format = "xml" }, "invalid --format"},
+		{"order", func(o *Options) { o.OrderBy = "random" }, "invalid --order-by"},
+		{"progress style", func(o *Options) { o.ProgressStyle = "dots" }, "invalid --progress-style"},
+		{"mode", func(o *Options) { o.Mode = "archive" }, "invalid --mode"},
+		{"negative limit", func(o *Options) { o.Limit = -1 }, "must not be negative"},
+		{"team without org", func(o *Options) { o.Team = "reviewers" }, "invalid --team"},
+		{"team", func(o *Options) { o.Team = "cli/reviewers" }, ""},
+		{"per page", func(o *Options) { o.PerPage = 101 }, "invalid --per-page"},
+		{"estimate", func(o *Options) { o.Estimate = 150 }, "invalid --estimate"},
+		{"plan and apply", func(o *Options) { o.Plan, o.ApplyPlan = true, "plan.json" }, "--plan and --apply-plan"},
+		{"json fields", func(o *Options) { o.JSONFields = []string{"id"} }, "--json-fields requires"},
+		{"json fields with json", func(o *Options) { o.Format, o.JSONFields = FormatJSON, []string{"id"} }, ""},
+		{"seed", func(o *Options) { o.Seed = 42 }, "--seed requires --sample"},
+		{"quiet and verbose", func(o *Options) { o.Quiet, o.Verbose = true, true }, "--quiet and --verbose"},
+		{"subject and repo", func(o *Options) { o.Subject, o.Repos = "https://github.com/cli/cli/pull/1", []string{"cli/cli"} }, "--subject"},
+		{"skip with custom rules", func(o *Options) { o.DeleteWhen, o.SkipClosedPRs = []string{"bot"}, true }, "only apply to the built-in rules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{
+				Format:        FormatTable,
+				OrderBy:       OrderUpdated,
+				ProgressStyle: ProgressBar,
+				ReportFormat:  FormatJSON,
+				Mode:          ModeDelete,
+				HookTiming:    HookEach,
+				PerPage:       100,
+			}
+			tt.set(opts)
+			err := validateOptions(opts)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateOptions() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateOptions() = %v, want an error about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+// repoConfirmation asks once per repository whether to flush its pending
+// notifications, for --confirm-per-repo.
+type repoConfirmation struct {
+	repos    []string
+	counts   map[string]int
+	current  int
+	approved map[string]bool
+}
+
+type confirmKeyMap struct {
+	Yes  key.Binding
+	No   key.Binding
+	All  key.Binding
+	Quit key.Binding
+}
+
+var confirmKeys = confirmKeyMap{
+	Yes: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "flush"),
+	),
+	No: key.NewBinding(
+		key.WithKeys("n", "enter"),
+		key.WithHelp("n", "keep"),
+	),
+	All: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "flush all"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c", "esc"),
+		key.WithHelp("q/esc", "keep the rest"),
+	),
+}
+
+func (k confirmKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Yes, k.No, k.All, k.Quit}
+}
+
+func (k confirmKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var questionStyle = lipgloss.NewStyle().Margin(1, 1)
+
+type confirmAllKeyMap struct {
+	Yes key.Binding
+	No  key.Binding
+}
+
+var confirmAllKeys = confirmAllKeyMap{
+	Yes: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "flush"),
+	),
+	No: key.NewBinding(
+		key.WithKeys("n", "enter", "q", "ctrl+c", "esc"),
+		key.WithHelp("n/q", "keep all"),
+	),
+}
+
+func (k confirmAllKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Yes, k.No}
+}
+
+func (k confirmAllKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+// updateConfirmingAll handles the answer to --interactive-confirm, which
+// may still be followed by --confirm-per-repo.
+func (m model) updateConfirmingAll(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, confirmAllKeys.Yes):
+		return m.confirmRepos(m.flushClient.Pending())
+	case key.Matches(msg, confirmAllKeys.No):
+		return m.applyPending(func(client.NotificationResult) bool { return false })
+	}
+	return m, nil
+}
+
+func (m model) confirmingAllView() string {
+	pending := m.flushClient.Pending()
+	question := fmt.Sprintf("Flush %d notifications? [y/N]", len(pending))
+	return m.fit(questionStyle).Render(question + "\n\n" + strings.TrimSuffix(client.FormatConfirmSample(pending), "\n"))
+}
+
+// confirmRepos asks per repository with --confirm-per-repo, otherwise all
+// pending matches get flushed.
+func (m model) confirmRepos(pending []client.NotificationResult) (tea.Model, tea.Cmd) {
+	if !m.flushClient.ConfirmingRepos() {
+		return m.startFlush(func(client.NotificationResult) bool { return true })
+	}
+	m.confirmation = newRepoConfirmation(pending)
+	m.uiMode = confirmingRepos
+	return m, nil
+}
+
+func newRepoConfirmation(pending []client.NotificationResult) repoConfirmation {
+	c := repoConfirmation{counts: map[string]int{}, approved: map[string]bool{}}
+	for _, res := range pending {
+		repo := res.Notification.Repository.FullName
+		if c.counts[repo] == 0 {
+			c.repos = append(c.repos, repo)
+		}
+		c.counts[repo]++
+	}
+	sort.Strings(c.repos)
+	return c
+}
+
+// updateConfirming handles the answers, once every repository is answered
+// the approved notifications get flushed.
+func (m model) updateConfirming(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := &m.confirmation
+	switch {
+	case key.Matches(msg, confirmKeys.Yes):
+		c.approved[c.repos[c.current]] = true
+		c.current++
+	case key.Matches(msg, confirmKeys.No):
+		c.current++
+	case key.Matches(msg, confirmKeys.All):
+		for _, repo := range c.repos[c.current:] {
+			c.approved[repo] = true
+		}
+		c.current = len(c.repos)
+	case key.Matches(msg, confirmKeys.Quit):
+		c.current = len(c.repos)
+	}
+	if c.current < len(c.repos) {
+		return m, nil
+	}
+
+	approved := c.approved
+	return m.startFlush(func(res client.NotificationResult) bool {
+		return approved[res.Notification.Repository.FullName]
+	})
+}
+
+func (m model) confirmingView() string {
+	c := m.confirmation
+	repo := c.repos[c.current]
+	question := fmt.Sprintf("Flush %d notifications from %s? [y/N/a(ll)]", c.counts[repo], repoStyle.Render(repo))
+	progress := userStyle.Render(fmt.Sprintf(" (%d/%d)", c.current+1, len(c.repos)))
+	return m.fit(questionStyle).Render(question + progress)
+}
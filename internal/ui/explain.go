@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+var (
+	selectedStyle = lipgloss.NewStyle().Foreground(magenta).Bold(true)
+	explainStyle  = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(gray).Padding(0, 1).MarginLeft(2)
+	labelStyle    = lipgloss.NewStyle().Foreground(gray).Width(10)
+)
+
+// explainView lists everything known about a result and why it was
+// flushed or kept.
+func (m model) explainView(res client.NotificationResult) string {
+	n := res.Notification
+	rows := [][2]string{
+		{"Type", n.Subject.Type},
+		{"Reason", fmt.Sprintf("%s (%s)", m.flushClient.ReasonLabel(n.Reason), n.Reason)},
+	}
+	if res.PR != nil {
+		state := res.PR.State
+		if res.PR.Merged {
+			state = "merged"
+		} else if res.PR.Draft {
+			state += ", draft"
+		}
+		rows = append(rows,
+			[2]string{"State", state},
+			[2]string{"Author", res.PR.User.Login},
+		)
+	}
+	if res.ReviewState != "" {
+		rows = append(rows, [2]string{"Reviews", res.ReviewState})
+	}
+	if res.Subscription != "" {
+		rows = append(rows, [2]string{"Thread", res.Subscription})
+	}
+	if res.Rule != "" {
+		rows = append(rows, [2]string{"Rule", res.Rule})
+	}
+	rows = append(rows,
+		[2]string{"Rules", strings.Join(client.ResultTags(res), ", ")},
+		[2]string{"Decision", m.decision(res)},
+		[2]string{"URL", n.HTMLUrl()},
+	)
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row[1] == "" {
+			continue
+		}
+		lines = append(lines, labelStyle.Render(row[0])+row[1])
+	}
+	return explainStyle.Render(strings.Join(lines, "\n"))
+}
+
+func (m model) decision(res client.NotificationResult) string {
+	switch {
+	case res.Err != nil:
+		return "failed: " + res.Err.Error()
+	case res.Deleted && (m.flushClient.DryRun() || res.Simulated):
+		return "would be flushed (dry run)"
+	case res.Deleted:
+		return "flushed"
+	case res.Declined:
+		return "kept, declined when confirming"
+	case res.KeptRecent:
+		return "kept, one of the most recent in its repository"
+	case res.Active:
+		return "kept, active discussion"
+	case res.Labeled:
+		return "kept, protected by its labels"
+	case res.RecentMention:
+		return "kept, recent mention"
+	case res.Fresh:
+		return "kept, newer than --newer-than"
+	case res.Limited:
+		return "kept, over --limit"
+	case res.Protected:
+		return "kept, protected"
+	case res.Excluded:
+		return "kept, repository excluded"
+	default:
+		return "kept, no rule matched"
+	}
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+// resultFilter narrows down the results list once a run is done.
+type resultFilter struct {
+	input       textinput.Model
+	onlyFlushed bool
+	onlyKept    bool
+	onlyBots    bool
+	// the list also shows without a filter once the cursor moved
+	listing    bool
+	cursor     int
+	explaining bool
+}
+
+type doneKeyMap struct {
+	Filter  key.Binding
+	Flushed key.Binding
+	Kept    key.Binding
+	Bots    key.Binding
+	Up      key.Binding
+	Down    key.Binding
+	Explain key.Binding
+	Copy    key.Binding
+	Undo    key.Binding
+	Quit    key.Binding
+}
+
+var doneKeys = doneKeyMap{
+	Filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	Flushed: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "flushed"),
+	),
+	Kept: key.NewBinding(
+		key.WithKeys("k"),
+		key.WithHelp("k", "kept"),
+	),
+	Bots: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "bots"),
+	),
+	Up: key.NewBinding(
+		key.WithKeys("up"),
+		key.WithHelp("↑/↓", "select"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down"),
+	),
+	Explain: key.NewBinding(
+		key.WithKeys("enter", "x"),
+		key.WithHelp("enter", "explain"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy URL"),
+	),
+	Undo: defaultKeyMap.Undo,
+	Quit: defaultKeyMap.Quit,
+}
+
+func (k doneKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Filter, k.Flushed, k.Kept, k.Bots, k.Up, k.Explain, k.Copy, k.Undo, k.Quit}
+}
+
+func (k doneKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+var filterStyle = lipgloss.NewStyle().Margin(0, 1)
+
+func newResultFilter() resultFilter {
+	input := textinput.New()
+	input.Prompt = "/ "
+	input.Placeholder = "title, repo or author"
+	return resultFilter{input: input}
+}
+
+func (f resultFilter) active() bool {
+	return f.input.Value() != "" || f.onlyFlushed || f.onlyKept || f.onlyBots
+}
+
+func (f resultFilter) matches(res client.NotificationResult) bool {
+	switch {
+	case f.onlyFlushed && !res.Deleted,
+		f.onlyKept && res.Deleted,
+		f.onlyBots && !res.BotPR:
+		return false
+	}
+	text := strings.ToLower(strings.TrimSpace(f.input.Value()))
+	if text == "" {
+		return true
+	}
+	fields := []string{res.Notification.Subject.Title, res.Notification.Repository.FullName}
+	if res.PR != nil {
+		fields = append(fields, res.PR.User.Login)
+	}
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), text) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateDone handles keys once the run is done.
+func (m model) updateDone(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.filter.input.Focused() {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.filter.input.Blur()
+			return m, nil
+		case tea.KeyEsc:
+			m.filter.input.Reset()
+			m.filter.input.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.filter.input, cmd = m.filter.input.Update(msg)
+		return m, cmd
+	}
+
+	m.status = ""
+	switch {
+	case key.Matches(msg, doneKeys.Filter):
+		return m, m.filter.input.Focus()
+	case key.Matches(msg, doneKeys.Flushed):
+		m.filter.onlyFlushed = !m.filter.onlyFlushed
+		m.filter.onlyKept = false
+	case key.Matches(msg, doneKeys.Kept):
+		m.filter.onlyKept = !m.filter.onlyKept
+		m.filter.onlyFlushed = false
+	case key.Matches(msg, doneKeys.Bots):
+		m.filter.onlyBots = !m.filter.onlyBots
+	case key.Matches(msg, doneKeys.Up):
+		m.filter.listing = true
+		m.filter.cursor--
+	case key.Matches(msg, doneKeys.Down):
+		m.filter.listing = true
+		m.filter.cursor++
+	case key.Matches(msg, doneKeys.Explain):
+		m.filter.listing = true
+		m.filter.explaining = !m.filter.explaining
+	case key.Matches(msg, doneKeys.Copy):
+		m.status = m.copySelectedUrl()
+	case key.Matches(msg, doneKeys.Undo):
+		return m.resubscribe()
+	case key.Matches(msg, doneKeys.Quit):
+		return m, tea.Quit
+	}
+	m.filter.cursor = min(max(m.filter.cursor, 0), len(m.matchingResults())-1)
+	return m, nil
+}
+
+func (m model) matchingResults() []client.NotificationResult {
+	matching := []client.NotificationResult{}
+	for _, res := range m.notificationResults {
+		if m.filter.matches(res) {
+			matching = append(matching, res)
+		}
+	}
+	return matching
+}
+
+// filterView lists the results matching the filter, as many as fit.
+func (m model) filterView() string {
+	if !m.filter.active() && !m.filter.input.Focused() && !m.filter.listing {
+		return ""
+	}
+	toggles := []string{}
+	if m.filter.onlyFlushed {
+		toggles = append(toggles, "flushed")
+	}
+	if m.filter.onlyKept {
+		toggles = append(toggles, "kept")
+	}
+	if m.filter.onlyBots {
+		toggles = append(toggles, "bots")
+	}
+
+	matching := m.matchingResults()
+	header := m.filter.input.View()
+	if len(toggles) > 0 {
+		header += userStyle.Render("  only " + strings.Join(toggles, ", "))
+	}
+	header += userStyle.Render("  " + strconv.Itoa(len(matching)) + " matching")
+
+	// leave room for the summary above and the help below
+	room := max(m.height-12, 3)
+	cursor := min(m.filter.cursor, len(matching)-1)
+	// scroll so that the selected result stays visible
+	first := max(cursor-room+1, 0)
+	lines := []string{header}
+	for i := first; i < min(first+room, len(matching)); i++ {
+		line := formatNotificationResult(m, matching[i])
+		if m.filter.listing && i == cursor {
+			line = selectedStyle.Render("›") + " " + line
+			if m.filter.explaining {
+				line += "\n" + m.explainView(matching[i])
+			}
+		} else if m.filter.listing {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if first+room < len(matching) {
+		lines = append(lines, userStyle.Render("…"))
+	}
+	return filterStyle.Render(lipgloss.JoinVertical(lipgloss.Left, lines...)) + "\n"
+}
+
+// copySelectedUrl copies the URL of the selected result and says how that
+// went.
+func (m model) copySelectedUrl() string {
+	matching := m.matchingResults()
+	if !m.filter.listing || len(matching) == 0 {
+		return "select a result with ↑/↓ first"
+	}
+	url := matching[min(m.filter.cursor, len(matching)-1)].Notification.HTMLUrl()
+	if url == "" {
+		return "this notification has no URL"
+	}
+	if err := copyToClipboard(url); err != nil {
+		return "cannot copy: " + err.Error()
+	}
+	return "copied!"
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/v2/pkg/browser"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+// review presents the pending matches one at a time, for --interactive.
+type review struct {
+	pending  []client.NotificationResult
+	current  int
+	approved map[string]bool
+}
+
+type reviewKeyMap struct {
+	Delete key.Binding
+	Keep   key.Binding
+	Open   key.Binding
+	Read   key.Binding
+	Quit   key.Binding
+}
+
+var reviewKeys = reviewKeyMap{
+	Delete: key.NewBinding(
+		key.WithKeys("d", "y"),
+		key.WithHelp("d", "delete"),
+	),
+	Keep: key.NewBinding(
+		key.WithKeys("k", "n", "enter"),
+		key.WithHelp("k", "keep"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	Read: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "mark read and keep"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c", "esc"),
+		key.WithHelp("q/esc", "keep the rest"),
+	),
+}
+
+func (k reviewKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Delete, k.Keep, k.Open, k.Read, k.Quit}
+}
+
+func (k reviewKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+type markedReadMsg struct{ err error }
+
+func markRead(m model, res client.NotificationResult) tea.Cmd {
+	return func() tea.Msg {
+		return markedReadMsg{m.flushClient.MarkRead(res)}
+	}
+}
+
+func newReview(pending []client.NotificationResult) review {
+	return review{pending: pending, approved: map[string]bool{}}
+}
+
+// updateReviewing handles the decision on the current notification, once
+// all are decided the approved ones get flushed.
+func (m model) updateReviewing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	r := &m.review
+	res := r.pending[r.current]
+	var cmd tea.Cmd
+	m.status = ""
+	switch {
+	case key.Matches(msg, reviewKeys.Delete):
+		r.approved[res.Notification.Id] = true
+		r.current++
+	case key.Matches(msg, reviewKeys.Keep):
+		r.current++
+	case key.Matches(msg, reviewKeys.Open):
+		if err := browser.New("", io.Discard, io.Discard).Browse(res.Notification.HTMLUrl()); err != nil {
+			m.status = "cannot open browser: " + err.Error()
+		}
+		return m, nil
+	case key.Matches(msg, reviewKeys.Read):
+		cmd = markRead(m, res)
+		r.current++
+	case key.Matches(msg, reviewKeys.Quit):
+		r.current = len(r.pending)
+	}
+	if r.current < len(r.pending) {
+		return m, cmd
+	}
+
+	approved := r.approved
+	next, flushCmd := m.startFlush(func(res client.NotificationResult) bool {
+		return approved[res.Notification.Id]
+	})
+	return next, tea.Batch(cmd, flushCmd)
+}
+
+func (m model) reviewingView() string {
+	r := m.review
+	res := r.pending[r.current]
+	progress := userStyle.Render(fmt.Sprintf("(%d/%d) ", r.current+1, len(r.pending)))
+	question := progress + formatNotificationResult(m, res) + "\nDelete it? [d/k/o/r]"
+	if m.status != "" {
+		question += "\n" + m.status
+	}
+	return m.fit(questionStyle).Render(question)
+}
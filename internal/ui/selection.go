@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+// selection is a checklist of the pending matches, for --select. All of
+// them start out checked.
+type selection struct {
+	pending []client.NotificationResult
+	cursor  int
+	checked map[string]bool
+}
+
+type selectionKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	All    key.Binding
+	Flush  key.Binding
+	Quit   key.Binding
+}
+
+var selectionKeys = selectionKeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" ", "x"),
+		key.WithHelp("space", "toggle"),
+	),
+	All: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "toggle all"),
+	),
+	Flush: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "flush checked"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c", "esc"),
+		key.WithHelp("q/esc", "keep all"),
+	),
+}
+
+func (k selectionKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Toggle, k.All, k.Flush, k.Quit}
+}
+
+func (k selectionKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+func newSelection(pending []client.NotificationResult) selection {
+	s := selection{pending: pending, checked: map[string]bool{}}
+	for _, res := range pending {
+		s.checked[res.Notification.Id] = true
+	}
+	return s
+}
+
+func (s selection) countChecked() int {
+	n := 0
+	for _, res := range s.pending {
+		if s.checked[res.Notification.Id] {
+			n++
+		}
+	}
+	return n
+}
+
+// updateSelecting toggles notifications until the checked ones get flushed.
+func (m model) updateSelecting(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := &m.selection
+	switch {
+	case key.Matches(msg, selectionKeys.Up):
+		s.cursor = max(s.cursor-1, 0)
+	case key.Matches(msg, selectionKeys.Down):
+		s.cursor = min(s.cursor+1, len(s.pending)-1)
+	case key.Matches(msg, selectionKeys.Toggle):
+		id := s.pending[s.cursor].Notification.Id
+		s.checked[id] = !s.checked[id]
+	case key.Matches(msg, selectionKeys.All):
+		all := s.countChecked() < len(s.pending)
+		for _, res := range s.pending {
+			s.checked[res.Notification.Id] = all
+		}
+	case key.Matches(msg, selectionKeys.Flush):
+		checked := s.checked
+		return m.startFlush(func(res client.NotificationResult) bool {
+			return checked[res.Notification.Id]
+		})
+	case key.Matches(msg, selectionKeys.Quit):
+		return m.applyPending(func(client.NotificationResult) bool { return false })
+	}
+	return m, nil
+}
+
+// selectingView lists as many of the pending matches as fit.
+func (m model) selectingView() string {
+	s := m.selection
+	header := fmt.Sprintf("Flush %d of %d notifications?", s.countChecked(), len(s.pending))
+
+	// leave room for the header and the help below
+	room := max(m.height-6, 3)
+	first := max(s.cursor-room+1, 0)
+	lines := []string{header}
+	for i := first; i < min(first+room, len(s.pending)); i++ {
+		res := s.pending[i]
+		box := "[ ]"
+		if s.checked[res.Notification.Id] {
+			box = "[x]"
+		}
+		pointer := " "
+		if i == s.cursor {
+			pointer = selectedStyle.Render("›")
+		}
+		title := res.Notification.Subject.Title
+		lines = append(lines, fmt.Sprintf("%s %s %s %s", pointer, box, repoStyle.Render("["+res.Notification.Repository.FullName+"]"), title))
+	}
+	if first+room < len(s.pending) {
+		lines = append(lines, userStyle.Render("…"))
+	}
+	return m.fit(questionStyle).Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -11,6 +13,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	humanize "github.com/dustin/go-humanize"
 
 	"github.com/soundmonster/gh-flush/internal/client"
@@ -21,6 +24,11 @@ type uiMode int
 const (
 	loadingNotifications uiMode = iota
 	flushingNotifications
+	confirmingAll
+	reviewing
+	selecting
+	confirmingRepos
+	countingDown
 	done
 )
 
@@ -31,16 +39,33 @@ type model struct {
 	numTotal            int
 	numProcessed        int
 	numFlushed          int
+	numSimulated        int
 	width               int
 	height              int
 	channelTo           chan string
 	channelFrom         chan string
 	spinner             spinner.Model
 	progress            progress.Model
+	progressStyle       string
 	keys                keyMap
 	help                help.Model
+	lastProgress        time.Time
+	lastRepo            string
+	showActivity        bool
+	status              string
+	filter              resultFilter
+	confirmation        repoConfirmation
+	review              review
+	selection           selection
+	undo                undoCountdown
+	reversible          *client.NotificationResult
+	err                 error
 }
 
+// stallAfter is how long the progress bar may sit still before we tell the
+// user that we're still working.
+const stallAfter = 5 * time.Second
+
 var (
 	red     = lipgloss.ANSIColor(1)
 	green   = lipgloss.ANSIColor(2)
@@ -51,23 +76,37 @@ var (
 	white   = lipgloss.ANSIColor(15)
 )
 var (
-	loadingStyle = lipgloss.NewStyle().Margin(1, 1)
-	helpStyle    = lipgloss.NewStyle().Margin(1, 1)
-	doneStyle    = lipgloss.NewStyle().Margin(1, 1)
-	deleteMark   = lipgloss.NewStyle().Foreground(red).SetString("⨉")
-	checkMark    = lipgloss.NewStyle().Foreground(green).SetString("✓")
-	repoStyle    = lipgloss.NewStyle().Foreground(magenta).Italic(true)
-	subjectStyle = lipgloss.NewStyle().Foreground(white)
-	deletedStyle = lipgloss.NewStyle().Foreground(gray).Strikethrough(true)
-	userStyle    = lipgloss.NewStyle().Foreground(gray)
-	tsStyle      = lipgloss.NewStyle().Foreground(blue).Italic(true)
+	loadingStyle   = lipgloss.NewStyle().Margin(1, 1)
+	helpStyle      = lipgloss.NewStyle().Margin(1, 1)
+	histogramStyle = lipgloss.NewStyle().Margin(0, 1, 1)
+	stallStyle     = lipgloss.NewStyle().Foreground(gray).Italic(true).Margin(0, 1)
+	doneStyle      = lipgloss.NewStyle().Margin(1, 1)
+	deleteMark     = lipgloss.NewStyle().Foreground(red).SetString("⨉")
+	checkMark      = lipgloss.NewStyle().Foreground(green).SetString("✓")
+	repoStyle      = lipgloss.NewStyle().Foreground(magenta).Italic(true)
+	subjectStyle   = lipgloss.NewStyle().Foreground(white)
+	deletedStyle   = lipgloss.NewStyle().Foreground(gray).Strikethrough(true)
+	userStyle      = lipgloss.NewStyle().Foreground(gray)
+	tsStyle        = lipgloss.NewStyle().Foreground(blue).Italic(true)
+	dryMark        = lipgloss.NewStyle().Foreground(gray).SetString(client.DryRunMark)
+	bannerStyle    = lipgloss.NewStyle().Foreground(yellow).Bold(true).Margin(0, 1)
 )
 
 type keyMap struct {
+	Log  key.Binding
+	Undo key.Binding
 	Quit key.Binding
 }
 
 var defaultKeyMap = keyMap{
+	Log: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "activity"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo unsubscribe"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("q", "ctrl+c", "esc"),
 		key.WithHelp("q/esc", "quit"),
@@ -75,11 +114,11 @@ var defaultKeyMap = keyMap{
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Quit}
+	return []key.Binding{k.Log, k.Undo, k.Quit}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{{k.Quit}}
+	return [][]key.Binding{k.ShortHelp()}
 }
 
 func newModel(flushClient *client.Client) model {
@@ -101,51 +140,141 @@ func newModel(flushClient *client.Client) model {
 		channelFrom:         make(chan string),
 		spinner:             s,
 		progress:            p,
+		progressStyle:       flushClient.ProgressStyle(),
 		keys:                defaultKeyMap,
 		help:                help.New(),
+		filter:              newResultFilter(),
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return tea.Batch(fetchNotifications(m), m.spinner.Tick)
+	cmds := []tea.Cmd{fetchNotifications(m), m.spinner.Tick}
+	if m.flushClient.DryRun() {
+		cmds = append(cmds, tea.Println(bannerStyle.Render(m.flushClient.DryRunBanner(false))))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width, m.height = msg.Width, msg.Height
+		m.resizeProgress()
 	case tea.KeyMsg:
+		if m.uiMode == done {
+			return m.updateDone(msg)
+		}
+		if m.uiMode == selecting {
+			return m.updateSelecting(msg)
+		}
+		if m.uiMode == reviewing {
+			return m.updateReviewing(msg)
+		}
+		if m.uiMode == confirmingAll {
+			return m.updateConfirmingAll(msg)
+		}
+		if m.uiMode == confirmingRepos {
+			return m.updateConfirming(msg)
+		}
+		if m.uiMode == countingDown {
+			return m.updateCountdown(msg)
+		}
 		switch {
+		case key.Matches(msg, defaultKeyMap.Log):
+			m.showActivity = !m.showActivity
+			return m, nil
+		case key.Matches(msg, defaultKeyMap.Undo):
+			return m.resubscribe()
 		case key.Matches(msg, defaultKeyMap.Quit):
-			// TODO make sure to quit immediately and abort all pending deletions
 			return m, tea.Quit
 		}
-	case processedNotificationMsg:
-		res := client.NotificationResult(msg)
-		m.numProcessed++
-		if res.Deleted {
-			m.numFlushed++
+	case processedNotificationsMsg:
+		lines := make([]string, 0, len(msg))
+		for _, res := range msg {
+			m.numProcessed++
+			if res.Deleted || res.Action != "" {
+				m.numFlushed++
+			}
+			if res.Simulated {
+				m.numSimulated++
+			}
+			m.notificationResults = append(m.notificationResults, res)
+			if m.flushClient.Reversible(res) {
+				m.reversible = &res
+			}
+			m.lastRepo = res.Notification.Repository.FullName
+			lines = append(lines, formatNotificationResult(m, res))
 		}
-		m.notificationResults = append(m.notificationResults, res)
+		m.lastProgress = time.Now()
+		m.numTotal = m.flushClient.NotificationCount()
 
-		// Update progress bar
-		progressCmd := m.progress.SetPercent(float64(m.numProcessed) / float64(m.numTotal))
+		// Update progress bar, once the total is known
+		var progressCmd tea.Cmd
+		if m.progressStyle == client.ProgressBar && m.flushClient.FetchDone() {
+			progressCmd = m.progress.SetPercent(float64(m.numProcessed) / float64(m.numTotal))
+		}
 
 		return m, tea.Batch(
 			progressCmd,
-			tea.Println(formatNotificationResult(m, res)),
-			recvProcessed(m), // download the next notification
+			tea.Println(strings.Join(lines, "\n")),
+			recvProcessed(m), // download the next notifications
 		)
 	case finishedMsg:
-		// Everything's been processed. We're done!
+		if pending := m.flushClient.Pending(); len(pending) > 0 {
+			if m.flushClient.Selecting() {
+				m.selection = newSelection(pending)
+				m.uiMode = selecting
+				return m, nil
+			}
+			if m.flushClient.Reviewing() {
+				m.review = newReview(pending)
+				m.uiMode = reviewing
+				return m, nil
+			}
+			if m.flushClient.ConfirmingAll() {
+				m.uiMode = confirmingAll
+				return m, nil
+			}
+			return m.confirmRepos(pending)
+		}
+		// Everything's been processed. We're done! Stay around so that the
+		// results can be filtered until the user quits.
 		m.uiMode = done
-		return m, tea.Quit // exit the program
+		return m, ringBell(m)
+	case markedReadMsg:
+		if msg.err != nil {
+			m.status = "cannot mark as read: " + msg.err.Error()
+		}
+		return m, nil
+	case resubscribedMsg:
+		if msg.err != nil {
+			m.status = "cannot undo unsubscribe: " + msg.err.Error()
+		} else {
+			m.status = "restored the subscription to " + msg.res.Notification.Subject.Title
+		}
+		return m, nil
+	case errMsg:
+		m.err = msg.error
+		return m, tea.Quit
 	case notificationsFetchedMsg:
 		m.uiMode = flushingNotifications
 		m.numTotal = m.flushClient.NotificationCount()
+		m.resizeProgress()
 		m.flushClient.ProcessNotifications()
+		m.lastProgress = time.Now()
 
-		return m, recvProcessed(m)
+		return m, tea.Batch(recvProcessed(m), heartbeat())
+	case countdownMsg:
+		if m.uiMode != countingDown {
+			return m, nil
+		}
+		return m.updateCountdown(msg)
+	case heartbeatMsg:
+		if m.uiMode != flushingNotifications {
+			return m, nil
+		}
+		m.numTotal = m.flushClient.NotificationCount()
+		return m, heartbeat()
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -161,29 +290,154 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
+	if m.err != nil {
+		return m.fit(doneStyle).Foreground(red).Render("gh flush: "+m.err.Error()) + "\n"
+	}
 	n := m.numTotal
 	w := lipgloss.Width(fmt.Sprintf("%d", n))
 
 	helpView := ""
+	keys := m.keys
+	keys.Undo.SetEnabled(m.reversible != nil)
 	var result string
 	switch m.uiMode {
 	case loadingNotifications:
-		helpView = helpStyle.Render(m.help.View(m.keys))
-		result = loadingStyle.Render(fmt.Sprintf("%s 🚽 Loading notifications ...", m.spinner.View()))
+		helpView = helpStyle.Render(m.help.View(keys))
+		result = m.fit(loadingStyle).Render(fmt.Sprintf("%s 🚽 Loading notifications ...", m.spinner.View()))
 	case flushingNotifications:
-		helpView = helpStyle.Render(m.help.View(m.keys))
+		helpView = helpStyle.Render(m.help.View(keys))
 		notificationCount := fmt.Sprintf(" %*d/%*d", w, m.numProcessed, w, n)
-		result = loadingStyle.Render(fmt.Sprintf("%s %s", m.progress.View(), notificationCount))
+		switch {
+		case m.progressStyle == client.ProgressNone:
+			// stay silent until done
+		case !m.flushClient.FetchDone():
+			// the total isn't known while later pages are fetched
+			result = m.fit(loadingStyle).Render(fmt.Sprintf("%s 🚽 Flushing notifications, processed %d, fetched %d so far ...", m.spinner.View(), m.numProcessed, n))
+		case m.progressStyle == client.ProgressPercentage:
+			result = loadingStyle.Render(fmt.Sprintf("%3d%%", m.numProcessed*100/max(n, 1)))
+		case m.progressStyle == client.ProgressSpinner:
+			result = m.fit(loadingStyle).Render(fmt.Sprintf("%s 🚽 Flushing notifications ...", m.spinner.View()))
+		default:
+			result = loadingStyle.Render(fmt.Sprintf("%s %s", m.progress.View(), notificationCount))
+		}
+		if m.progressStyle != client.ProgressNone && time.Since(m.lastProgress) > stallAfter {
+			note := "(still working"
+			if m.lastRepo != "" {
+				note += ", last: " + m.lastRepo
+			}
+			result += "\n" + m.fit(stallStyle).Render(note+")")
+		}
+		if m.status != "" {
+			result += "\n" + m.fit(stallStyle).Render(m.status)
+		}
+		result += m.activityView()
+	case selecting:
+		helpView = helpStyle.Render(m.help.View(selectionKeys))
+		result = m.selectingView()
+	case reviewing:
+		helpView = helpStyle.Render(m.help.View(reviewKeys))
+		result = m.reviewingView()
+	case confirmingAll:
+		helpView = helpStyle.Render(m.help.View(confirmAllKeys))
+		result = m.confirmingAllView()
+	case confirmingRepos:
+		helpView = helpStyle.Render(m.help.View(confirmKeys))
+		result = m.confirmingView()
+	case countingDown:
+		helpView = helpStyle.Render(m.help.View(undoKeys))
+		result = m.countdownView()
 	case done:
 		boldStyle := lipgloss.NewStyle().Bold(true)
 		processed := boldStyle.Render(strconv.Itoa(m.numProcessed))
 		flushed := boldStyle.Render(strconv.Itoa(m.numFlushed))
 		done := boldStyle.Render("Done!")
-		result = doneStyle.Render(fmt.Sprintf("🎉 %s Processed %s notifications, flushed %s 🚽", done, processed, flushed))
+		if m.flushClient.NothingMatched() {
+			result = m.fit(doneStyle).Render(fmt.Sprintf("%s Processed %s notifications. %s", done, processed, client.NothingMatchedMessage))
+		} else {
+			result = m.fit(doneStyle).Render(fmt.Sprintf("🎉 %s Processed %s notifications, flushed %s 🚽", done, processed, flushed))
+		}
+		if comparison := m.flushClient.RunComparison(); comparison != "" {
+			result += "\n" + m.fit(stallStyle).Render(comparison)
+		}
+		if truncated := m.flushClient.Truncated(); truncated != "" {
+			result += "\n" + m.fit(stallStyle).Render("Not all notifications were fetched, "+truncated)
+		}
+		if m.flushClient.Verbose() && m.flushClient.Duplicates() > 0 {
+			result += "\n" + stallStyle.Render(fmt.Sprintf("Dropped %d duplicate notifications", m.flushClient.Duplicates()))
+		}
+		if workers := m.flushClient.SettledWorkers(); workers > 0 {
+			result += "\n" + stallStyle.Render(fmt.Sprintf("Delete workers settled at %d", workers))
+		}
+		if apiErrors := m.flushClient.APIErrors(); apiErrors.Count > 0 {
+			result += "\n" + histogramStyle.Foreground(red).Render(strings.TrimSpace(apiErrors.String()))
+		}
+		if report := m.flushClient.EstimateReport(); report != "" {
+			result += "\n" + m.fit(doneStyle).Render(report)
+		}
+		if report := m.flushClient.FailureReport(); report != "" {
+			result += "\n" + m.fit(histogramStyle.Foreground(red)).Render(strings.TrimSpace(report))
+		}
+		if report := m.flushClient.PermissionReport(); report != "" {
+			style := doneStyle
+			if m.flushClient.PermissionsFailed() {
+				style = histogramStyle.Foreground(red)
+			}
+			result += "\n" + m.fit(style).Render(report)
+		}
+		if len(m.notificationResults) > 0 {
+			result += "\n" + histogramStyle.Render(formatAgeHistogram(m.notificationResults))
+			result += "\n" + histogramStyle.Render(strings.TrimSpace(m.flushClient.FormatReasonSummary(m.notificationResults)))
+		}
+		if m.flushClient.ShowUnflushedRepos() {
+			result += "\n" + histogramStyle.Render(strings.TrimSpace(client.FormatUnflushedRepos(m.notificationResults)))
+		}
+		if slowest := client.FormatSlowest(m.notificationResults); slowest != "" {
+			result += "\n" + histogramStyle.Render(strings.TrimSpace(slowest))
+		}
+		if m.flushClient.MultiHost() {
+			result += "\n" + histogramStyle.Render(strings.TrimSpace(client.FormatHostSummary(m.notificationResults)))
+		}
+		if m.flushClient.DryRun() {
+			result += "\n" + m.fit(bannerStyle).Render(m.flushClient.DryRunBanner(true)) + "\n"
+		} else if m.flushClient.Sampling() {
+			result += "\n" + m.fit(bannerStyle).Render(fmt.Sprintf("Sample run: really flushed the %d notifications tagged [sample], the rest were dry-run", m.numFlushed-m.numSimulated)) + "\n"
+		}
+		result += m.filterView()
+		if m.status != "" {
+			result += stallStyle.Render(m.status) + "\n"
+		}
+		doneKeys := doneKeys
+		doneKeys.Undo.SetEnabled(m.reversible != nil)
+		helpView = helpStyle.Render(m.help.View(doneKeys))
 	}
 	return result + helpView
 }
 
+const (
+	minProgressWidth = 10
+	maxProgressWidth = 100
+)
+
+// resizeProgress fits the progress bar and the count next to it into the
+// terminal width.
+func (m *model) resizeProgress() {
+	if m.width == 0 {
+		return
+	}
+	digits := len(strconv.Itoa(m.numTotal))
+	suffix := 2 + 2*digits + 1 // " " + " n/n"
+	width := m.width - loadingStyle.GetHorizontalFrameSize() - suffix
+	m.progress.Width = min(max(width, minProgressWidth), maxProgressWidth)
+}
+
+// fit wraps text rendered with style at the terminal width.
+func (m model) fit(style lipgloss.Style) lipgloss.Style {
+	if m.width == 0 {
+		return style
+	}
+	return style.Width(max(m.width-style.GetHorizontalFrameSize(), 1))
+}
+
 func tag(s string, c lipgloss.TerminalColor) string {
 	return lipgloss.NewStyle().Foreground(c).Render(fmt.Sprintf("[%s]", s))
 }
@@ -199,6 +453,9 @@ func formatNotificationResult(m model, res client.NotificationResult) string {
 		subject = subjectStyle.Render(res.Notification.Subject.Title)
 	}
 	repo := repoStyle.Render(res.Notification.Repository.FullName)
+	if res.RenamedFrom != "" {
+		repo += userStyle.Render(" (was " + res.RenamedFrom + ")")
+	}
 	user := ""
 	if res.PR != nil {
 		user = userStyle.Render(" by " + res.PR.User.Login)
@@ -206,42 +463,169 @@ func formatNotificationResult(m model, res client.NotificationResult) string {
 	ts := tsStyle.Render(" " + humanize.Time(res.Notification.UpdatedAt))
 
 	tags := ""
+	if m.flushClient.MultiHost() {
+		tags += " " + tag(res.Notification.Host(), gray)
+	}
+	if res.Notification.Reason != "" {
+		tags += " " + tag(m.flushClient.ReasonLabel(res.Notification.Reason), gray)
+	}
 	if res.BotPR {
 		tags += " " + tag("bot", yellow)
 	}
-	if res.ClosedPR {
+	if res.MergedPR {
+		tags += " " + tag("merged", red)
+	} else if res.ClosedPR {
 		tags += " " + tag("closed", red)
 	}
+	if res.ClosedIssue {
+		tags += " " + tag("closed issue", red)
+	}
+	if res.OwnPR && res.MergedPR {
+		tags += " " + tag("own merged", red)
+	}
+	if res.StaleDraft {
+		tags += " " + tag("stale-draft", yellow)
+	}
+	if res.Old {
+		tags += " " + tag("old", yellow)
+	}
+	if res.ObsoleteStateChange {
+		tags += " " + tag("state-change", yellow)
+	}
+	if res.Commit {
+		tags += " " + tag("commit", blue)
+	}
 	if res.Read {
 		tags += " " + tag("read", magenta)
 	}
-	result := fmt.Sprintf("%s %s in %s%s%s%s", action, subject, repo, user, ts, tags)
-	if m.width < lipgloss.Width(result) {
-		lineBreak := "\n  "
-		result = fmt.Sprintf("%s %s%sin %s%s%s%s", action, subject, lineBreak, repo, user, ts, tags)
+	if res.NewActivity {
+		tags += " " + tag("new-activity", green)
+	}
+	if res.ReviewState == client.ReviewChangesRequested {
+		tags += " " + tag("changes-requested", yellow)
+	}
+	if res.Subscription != "" {
+		tags += " " + tag(res.Subscription, blue)
 	}
-	return result
+	if res.LastCommentMine {
+		tags += " " + tag("last-comment-mine", yellow)
+	}
+	if res.Action != "" {
+		tags += " " + tag(res.Action, blue)
+	}
+	if res.Unsubscribed {
+		tags += " " + tag("unsubscribed", blue)
+	}
+	if res.KeptRecent {
+		tags += " " + tag("recent", green)
+	} else if res.Active {
+		tags += " " + tag("active", green)
+	} else if res.Labeled {
+		tags += " " + tag("labeled", green)
+	} else if res.RecentMention {
+		tags += " " + tag("recent-mention", green)
+	} else if res.Fresh {
+		tags += " " + tag("fresh", green)
+	} else if res.Protected {
+		tags += " " + tag("protected", green)
+	}
+	if res.Limited {
+		tags += " " + tag("over-limit", gray)
+	}
+	if res.Excluded {
+		tags += " " + tag("excluded", gray)
+	}
+	if res.Declined {
+		tags += " " + tag("declined", green)
+	}
+	if res.Err != nil {
+		tags += " " + tag("failed: "+res.Err.Error(), red)
+	}
+	if m.flushClient.DryRun() || res.Simulated {
+		action = dryMark.Render() + " " + action
+	} else if res.Deleted && m.flushClient.Sampling() {
+		tags += " " + tag("sample", red)
+	}
+	return layoutResult(m.width, action+" "+subject, "in "+repo+user+ts, tags)
 }
 
-type processedNotificationMsg client.NotificationResult
+// layoutResult fits a result into the terminal width: on one line if it
+// fits, otherwise with the details on a second line. Lines that are still
+// too long are truncated with an ellipsis, but the tags stay visible, on a
+// line of their own if need be.
+func layoutResult(width int, title, details, tags string) string {
+	const indent = "  "
+	line := title + " " + details + tags
+	if width <= 0 || lipgloss.Width(line) <= width {
+		return line
+	}
+
+	first := ansi.Truncate(title, width, "…")
+	second := indent + details
+	tagsWidth := lipgloss.Width(tags)
+	switch {
+	case lipgloss.Width(second)+tagsWidth <= width:
+		return first + "\n" + second + tags
+	case len(indent)+minDetailsWidth+tagsWidth <= width:
+		return first + "\n" + ansi.Truncate(second, width-tagsWidth, "…") + tags
+	case tags == "":
+		return first + "\n" + ansi.Truncate(second, width, "…")
+	default:
+		tagLines := ansi.Wrap(indent+strings.TrimLeft(tags, " "), width, "")
+		return first + "\n" + ansi.Truncate(second, width, "…") + "\n" + tagLines
+	}
+}
+
+// minDetailsWidth is the least room worth giving the repo and author when
+// squeezing them onto a line with the tags.
+const minDetailsWidth = 12
+
+func formatAgeHistogram(results []client.NotificationResult) string {
+	buckets := client.AgeHistogram(results, time.Now())
+	flushed, kept := client.HistogramBars(buckets, 30)
+	lines := []string{userStyle.Render("Age of notifications")}
+	width := client.LabelWidth(buckets)
+	for i, b := range buckets {
+		bar := lipgloss.NewStyle().Foreground(red).Render(flushed[i]) + lipgloss.NewStyle().Foreground(green).Render(kept[i])
+		counts := userStyle.Render(fmt.Sprintf("%d flushed, %d kept", b.Flushed, b.Kept))
+		lines = append(lines, fmt.Sprintf("%-*s %s %s", width, b.Label, bar, counts))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+type processedNotificationsMsg []client.NotificationResult
 type finishedMsg bool
 
+// maxBatch caps how many results are rendered per update, so that the UI
+// keeps up with fast deletions without rendering too much at once.
+const maxBatch = 50
+
 func recvProcessed(m model) tea.Cmd {
-	notification, ok := m.flushClient.GetNotificationResult()
 	return func() tea.Msg {
+		results, ok := m.flushClient.GetNotificationResults(maxBatch)
 		if ok {
-			return processedNotificationMsg(notification)
+			return processedNotificationsMsg(results)
 		} else {
 			return finishedMsg(true)
 		}
 	}
 }
 
+type heartbeatMsg time.Time
+
+// heartbeat re-renders the view every second so that stalls become visible.
+func heartbeat() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return heartbeatMsg(t) })
+}
+
 type notificationsFetchedMsg bool
+type errMsg struct{ error }
 
 func fetchNotifications(m model) tea.Cmd {
 	return func() tea.Msg {
-		m.flushClient.FetchNotifications()
+		if err := m.flushClient.FetchNotifications(); err != nil {
+			return errMsg{err}
+		}
 		return notificationsFetchedMsg(true)
 	}
 }
@@ -254,8 +638,36 @@ func max(a, b int) int {
 }
 
 func Run(flushClient *client.Client) {
-	if _, err := tea.NewProgram(newModel(flushClient)).Run(); err != nil {
+	finalModel, err := tea.NewProgram(newModel(flushClient)).Run()
+	if err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
+	if m, ok := finalModel.(model); ok && m.err != nil {
+		os.Exit(1)
+	}
+}
+
+// activityLines is how many recent API requests the activity panel shows.
+const activityLines = 6
+
+var activityStyle = lipgloss.NewStyle().Foreground(gray).Border(lipgloss.NormalBorder(), true, false, false).BorderForeground(gray).Margin(0, 1)
+
+// activityView shows the most recent API requests once toggled with l.
+func (m model) activityView() string {
+	if !m.showActivity {
+		return ""
+	}
+	lines := m.flushClient.RecentActivity(activityLines)
+	if len(lines) == 0 {
+		lines = []string{"no API requests yet"}
+	}
+	return "\n" + m.fit(activityStyle).Render(strings.Join(lines, "\n"))
+}
+
+func ringBell(m model) tea.Cmd {
+	return func() tea.Msg {
+		m.flushClient.RingBell()
+		return nil
+	}
 }
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLayoutResultFitsWidth(t *testing.T) {
+	results := []struct {
+		name                 string
+		title, details, tags string
+	}{
+		{"short", "✓ Fix typo", "in cli/cli by octocat", " [mention]"},
+		{"long title", "⨉ " + strings.Repeat("Refactor the notification fetcher ", 6), "in soundmonster/gh-flush by dependabot[bot] 3 days ago", " [ci] [bot] [merged]"},
+		{"long repo", "✓ Bump", "in some-organization-with-a-long-name/and-an-even-longer-repository-name by someone 2 hours ago", " [subscribed]"},
+		{"many tags", "⨉ Release", "in cli/go-gh", " [Review requested] [bot] [closed] [stale-draft] [changes-requested] [last-comment-mine] [over-limit]"},
+		{"no tags", "✓ " + strings.Repeat("x", 150), "in cli/cli", ""},
+	}
+	for _, width := range []int{20, 40, 80, 200} {
+		for _, res := range results {
+			out := layoutResult(width, res.title, res.details, res.tags)
+			for _, line := range strings.Split(out, "\n") {
+				if w := lipgloss.Width(line); w > width {
+					t.Errorf("%s at %d columns: line is %d wide: %q", res.name, width, w, line)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/soundmonster/gh-flush/internal/client"
+)
+
+// undoCountdown is the last chance to back out before the confirmed
+// matches get deleted, for --undo-window.
+type undoCountdown struct {
+	left     time.Duration
+	approve  func(client.NotificationResult) bool
+	approved int
+}
+
+type undoKeyMap struct {
+	Undo key.Binding
+	Quit key.Binding
+}
+
+var undoKeys = undoKeyMap{
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c", "esc"),
+		key.WithHelp("q/esc", "quit without deleting"),
+	),
+}
+
+func (k undoKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Undo, k.Quit}
+}
+
+func (k undoKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
+type countdownMsg time.Time
+
+func countdown() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return countdownMsg(t) })
+}
+
+// startFlush deletes the pending matches that approve accepts, after the
+// undo window if there is one.
+func (m model) startFlush(approve func(client.NotificationResult) bool) (tea.Model, tea.Cmd) {
+	window := m.flushClient.UndoWindow()
+	if window <= 0 {
+		return m.applyPending(approve)
+	}
+	approved := 0
+	for _, res := range m.flushClient.Pending() {
+		if approve(res) {
+			approved++
+		}
+	}
+	if approved == 0 {
+		return m.applyPending(approve)
+	}
+	m.undo = undoCountdown{left: window, approve: approve, approved: approved}
+	m.uiMode = countingDown
+	return m, countdown()
+}
+
+func (m model) applyPending(approve func(client.NotificationResult) bool) (tea.Model, tea.Cmd) {
+	m.flushClient.ApplyPending(approve)
+	m.uiMode = flushingNotifications
+	return m, recvProcessed(m)
+}
+
+func (m model) updateCountdown(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, undoKeys.Undo):
+			return m.applyPending(func(client.NotificationResult) bool { return false })
+		case key.Matches(msg, undoKeys.Quit):
+			return m, tea.Quit
+		}
+	case countdownMsg:
+		m.undo.left -= time.Second
+		if m.undo.left <= 0 {
+			return m.applyPending(m.undo.approve)
+		}
+		return m, countdown()
+	}
+	return m, nil
+}
+
+func (m model) countdownView() string {
+	seconds := int((m.undo.left + time.Second - 1) / time.Second)
+	question := fmt.Sprintf("Flushing %d notifications in %ds… press u to undo", m.undo.approved, seconds)
+	return m.fit(questionStyle).Render(bannerStyle.UnsetMargins().Render(question))
+}
+
+type resubscribedMsg struct {
+	res client.NotificationResult
+	err error
+}
+
+// resubscribe undoes the most recent unsubscribe. Deleting a thread and
+// marking it as read cannot be undone.
+func (m model) resubscribe() (tea.Model, tea.Cmd) {
+	if m.reversible == nil {
+		return m, nil
+	}
+	res := *m.reversible
+	m.reversible = nil
+	return m, func() tea.Msg {
+		return resubscribedMsg{res, m.flushClient.Resubscribe(res)}
+	}
+}
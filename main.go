@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/soundmonster/gh-flush/internal/client"
@@ -9,19 +10,62 @@ import (
 
 func main() {
 	client := client.NewClient()
+	if err := client.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "gh flush:", err)
+		os.Exit(1)
+	}
+	if client.ListingTypes() {
+		if err := client.FetchNotifications(); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush:", err)
+			os.Exit(1)
+		}
+		client.PrintTypes()
+		return
+	}
+	if client.VerifyingPlan() {
+		if err := client.FetchNotifications(); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush:", err)
+			os.Exit(1)
+		}
+		client.ProcessNotifications()
+		if err := client.PrintPlanDrift(); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush:", err)
+			os.Exit(1)
+		}
+		os.Exit(client.ExitCode())
+	}
+	if client.Planning() {
+		if err := client.FetchNotifications(); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush:", err)
+			os.Exit(1)
+		}
+		client.ProcessNotifications()
+		client.PrintPlan()
+		os.Exit(client.ExitCode())
+	}
 	if isTerminal() {
 		ui.Run(client)
 	} else {
-		client.FetchNotifications()
+		client.PromptOnStderr()
+		if client.Interactive() {
+			fmt.Fprintln(os.Stderr, "gh flush: confirming deletions needs a terminal")
+			os.Exit(1)
+		}
+		if err := client.FetchNotifications(); err != nil {
+			fmt.Fprintln(os.Stderr, "gh flush:", err)
+			os.Exit(1)
+		}
 		client.ProcessNotifications()
 		client.PrintResults()
+		client.RingBell()
 	}
+	os.Exit(client.ExitCode())
 }
 
 func isTerminal() bool {
 	fi, err := os.Stdout.Stat()
 	if err != nil {
-		panic(err)
+		return false
 	}
 	return (fi.Mode() & os.ModeCharDevice) == os.ModeCharDevice
 }